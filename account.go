@@ -6,6 +6,7 @@ import (
 
 // AccountResponse defines the data returned from an Account API call.
 type AccountResponse struct {
+	RawJSON      `json:"-"`
 	Name         string `json:"name"`
 	PrimaryEmail string `json:"primary_email_address"`
 }
@@ -0,0 +1,72 @@
+package convertkit
+
+import "context"
+
+// SubscriberAPI is the set of Client methods for managing subscribers.
+type SubscriberAPI interface {
+	Subscribers(req SubscribersRequest) (*SubscribersResponse, error)
+	Subscriber(id SubscriberID) (*SubscriberResponse, error)
+	SubscriberTags(id SubscriberID) (*SubscriberTagsResponse, error)
+	UpdateSubscriber(req UpdateSubscriberRequest) (*UpdateSubscriberResponse, error)
+	UnsubscribeSubscriber(email string) (*UnsubscribeSubscriberResponse, error)
+	UpsertSubscriber(req UpsertSubscriberRequest) (*UpsertSubscriberResponse, error)
+}
+
+// TagAPI is the set of Client methods for managing tags and tagging
+// subscribers.
+type TagAPI interface {
+	Tags() (*TagsResponse, error)
+	CreateTags(tags ...string) (*CreateTagsResponse, error)
+	EnsureTags(names ...string) (map[string]Tag, error)
+	TagSubscriber(req TagSubscriberRequest) (*TagSubscriberResponse, error)
+	TagSubscriberByName(tag string, req TagSubscriberRequest) (*TagSubscriberResponse, error)
+	UntagSubscriber(req UntagSubscriberRequest) (*UntagSubscriberResponse, error)
+	UntagSubscriberByName(tag string, req UntagSubscriberRequest) (*UntagSubscriberResponse, error)
+	TagSubscriptions(req TagSubscriptionsRequest) (*TagSubscriptionsResponse, error)
+	BulkTag(ctx context.Context, tag TagID, targets []BulkTarget, opts BulkOptions) (*BulkSummary, error)
+	BulkUntag(ctx context.Context, tag TagID, targets []BulkTarget, opts BulkOptions) (*BulkSummary, error)
+}
+
+// FormAPI is the set of Client methods for forms and their subscriptions.
+type FormAPI interface {
+	Forms() (*FormsResponse, error)
+	SubscribeToForm(req SubscribeToFormRequest) (*SubscribeToFormResponse, error)
+	SubscribeToFormByName(form string, req SubscribeToFormRequest) (*SubscribeToFormResponse, error)
+	FormSubscriptions(req FormSubscriptionsRequest) (*FormSubscriptionsResponse, error)
+}
+
+// SequenceAPI is the set of Client methods for sequences and their
+// subscriptions.
+type SequenceAPI interface {
+	Sequences() (*SequencesResponse, error)
+	SubscribeToSequence(req SubscribeToSequenceRequest) (*SubscribeToSequenceResponse, error)
+	SubscribeToSequenceByName(sequence string, req SubscribeToSequenceRequest) (*SubscribeToSequenceResponse, error)
+	SequenceSubscriptions(req SequenceSubscriptionsRequest) (*SequenceSubscriptionsResponse, error)
+}
+
+// WebhookAPI is the set of Client methods for managing webhooks.
+type WebhookAPI interface {
+	CreateWebhook(req CreateWebhookRequest) (*CreateWebhookResponse, error)
+	DeleteWebhook(ruleID WebhookRuleID) (*DeleteWebhookResponse, error)
+}
+
+// API is every endpoint supported by Client. Depend on it (or one of the
+// smaller interfaces it is made of) instead of *Client so that tests can swap
+// in a fake like convertkittest.Mock.
+type API interface {
+	Account() (*AccountResponse, error)
+	SubscriberAPI
+	TagAPI
+	FormAPI
+	SequenceAPI
+	WebhookAPI
+}
+
+var (
+	_ API           = (*Client)(nil)
+	_ SubscriberAPI = (*Client)(nil)
+	_ TagAPI        = (*Client)(nil)
+	_ FormAPI       = (*Client)(nil)
+	_ SequenceAPI   = (*Client)(nil)
+	_ WebhookAPI    = (*Client)(nil)
+)
@@ -0,0 +1,239 @@
+package convertkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BulkTarget is a subscriber to tag or untag with BulkTag or BulkUntag. Set
+// either Email or SubscriberID. Tagging by ID and untagging by email each take
+// an extra request to look the subscriber up.
+type BulkTarget struct {
+	Email        string
+	SubscriberID SubscriberID
+}
+
+func (bt BulkTarget) String() string {
+	if bt.Email != "" {
+		return bt.Email
+	}
+	return fmt.Sprintf("subscriber %d", bt.SubscriberID)
+}
+
+// BulkEmails returns a BulkTarget for each email address.
+func BulkEmails(emails ...string) []BulkTarget {
+	ret := make([]BulkTarget, len(emails))
+	for i, email := range emails {
+		ret[i] = BulkTarget{Email: email}
+	}
+	return ret
+}
+
+// BulkSubscriberIDs returns a BulkTarget for each subscriber ID.
+func BulkSubscriberIDs(ids ...SubscriberID) []BulkTarget {
+	ret := make([]BulkTarget, len(ids))
+	for i, id := range ids {
+		ret[i] = BulkTarget{SubscriberID: id}
+	}
+	return ret
+}
+
+// BulkResult is the outcome of tagging or untagging a single target.
+type BulkResult struct {
+	// Index is the target's position in the list passed to BulkTag or
+	// BulkUntag.
+	Index  int
+	Target BulkTarget
+	// SubscriberID is the target's ID, if it is known.
+	SubscriberID SubscriberID
+	Err          error
+}
+
+// BulkOptions configures BulkTag and BulkUntag. The zero value is ready to use.
+type BulkOptions struct {
+	// Workers defaults to 4.
+	Workers int
+	// Limiter defaults to the Client's RateLimiter. Set it to share a limit
+	// across Clients.
+	Limiter *RateLimiter
+	// Skip is the number of targets at the start of the list to skip. Set it
+	// to BulkSummary.Completed from an interrupted run to resume it.
+	Skip int
+	// OnResult, if set, is called with the result of each target as it
+	// finishes. Calls are never concurrent, but they aren't in list order.
+	OnResult func(BulkResult)
+}
+
+// BulkSummary is the result of a BulkTag or BulkUntag call.
+type BulkSummary struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Skipped   int
+	// Completed is the number of targets at the start of the list that have
+	// finished, including skipped ones. Pass it as BulkOptions.Skip to resume
+	// a cancelled run without repeating work.
+	Completed int
+}
+
+func (s BulkSummary) String() string {
+	return fmt.Sprintf("%d targets: %d succeeded, %d failed, %d skipped", s.Total, s.Succeeded, s.Failed, s.Skipped)
+}
+
+// BulkTag applies a tag to every target using a pool of workers that share a
+// rate limiter. Failures are reported per target through opts.OnResult and the
+// summary rather than stopping the run. If ctx is cancelled before every
+// target has finished, targets that haven't started are left undone and
+// ctx.Err() is returned along with the summary so far.
+func (c *Client) BulkTag(ctx context.Context, tag TagID, targets []BulkTarget, opts BulkOptions) (*BulkSummary, error) {
+	return c.bulk(ctx, targets, opts, func(wait func() error, t BulkTarget) (SubscriberID, error) {
+		email := t.Email
+		if email == "" {
+			err := wait()
+			if err != nil {
+				return 0, err
+			}
+			resp, err := c.Subscriber(t.SubscriberID)
+			if err != nil {
+				return t.SubscriberID, err
+			}
+			email = resp.Subscriber.Email
+		}
+		err := wait()
+		if err != nil {
+			return t.SubscriberID, err
+		}
+		resp, err := c.TagSubscriber(TagSubscriberRequest{TagID: tag, Email: email})
+		if err != nil {
+			return t.SubscriberID, err
+		}
+		return resp.Subscription.Subscriber.ID, nil
+	})
+}
+
+// BulkUntag removes a tag from every target. It works like BulkTag.
+func (c *Client) BulkUntag(ctx context.Context, tag TagID, targets []BulkTarget, opts BulkOptions) (*BulkSummary, error) {
+	return c.bulk(ctx, targets, opts, func(wait func() error, t BulkTarget) (SubscriberID, error) {
+		id := t.SubscriberID
+		if id == 0 {
+			err := wait()
+			if err != nil {
+				return 0, err
+			}
+			resp, err := c.Subscribers(SubscribersRequest{Email: t.Email})
+			if err != nil {
+				return 0, err
+			}
+			if len(resp.Subscribers) == 0 {
+				return 0, fmt.Errorf("no subscriber with email address %q", t.Email)
+			}
+			id = resp.Subscribers[0].ID
+		}
+		err := wait()
+		if err != nil {
+			return id, err
+		}
+		_, err = c.UntagSubscriber(UntagSubscriberRequest{SubscriberID: id, TagID: tag})
+		return id, err
+	})
+}
+
+// bulkFunc processes a single target. It must call wait before each request
+// and return its error if it fails.
+type bulkFunc func(wait func() error, t BulkTarget) (SubscriberID, error)
+
+func (c *Client) bulk(ctx context.Context, targets []BulkTarget, opts BulkOptions, fn bulkFunc) (*BulkSummary, error) {
+	limiter := opts.Limiter
+	if limiter == nil {
+		limiter = c.RateLimiter()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	skip := opts.Skip
+	if skip > len(targets) {
+		skip = len(targets)
+	}
+	if skip < 0 {
+		skip = 0
+	}
+	wait := func() error {
+		return limiter.Wait(ctx)
+	}
+
+	sum := BulkSummary{Total: len(targets), Skipped: skip}
+	jobs := make(chan int)
+	results := make(chan BulkResult)
+	go func() {
+		defer close(jobs)
+		for i := skip; i < len(targets); i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				t := targets[i]
+				id, err := fn(wait, t)
+				if ctx.Err() != nil && err == ctx.Err() {
+					// Cancelled before finishing, so it's left for the
+					// next run.
+					continue
+				}
+				if id == 0 {
+					id = t.SubscriberID
+				}
+				results <- BulkResult{Index: i, Target: t, SubscriberID: id, Err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	p := progress{n: skip}
+	for res := range results {
+		if res.Err != nil {
+			sum.Failed++
+		} else {
+			sum.Succeeded++
+		}
+		p.finish(res.Index + 1)
+		if opts.OnResult != nil {
+			opts.OnResult(res)
+		}
+	}
+	sum.Completed = p.n
+	if sum.Completed < sum.Total {
+		return &sum, ctx.Err()
+	}
+	return &sum, nil
+}
+
+// progress tracks how many items at the start of a list have finished when
+// they finish out of order. Items are numbered from 1.
+type progress struct {
+	n    int
+	done map[int]bool
+}
+
+func (p *progress) finish(i int) {
+	if p.done == nil {
+		p.done = make(map[int]bool)
+	}
+	p.done[i] = true
+	for p.done[p.n+1] {
+		delete(p.done, p.n+1)
+		p.n++
+	}
+}
@@ -0,0 +1,167 @@
+package convertkit_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func bulkServer(t *testing.T) (*convertkittest.Server, *convertkit.Client) {
+	t.Helper()
+	srv := convertkittest.NewServer("fake-secret-key")
+	t.Cleanup(srv.Close)
+	srv.Seed(convertkittest.Fixtures{
+		Tags: []convertkit.Tag{{ID: 14, Name: "Launch"}},
+		Subscribers: []convertkit.Subscriber{
+			{ID: 1, Email: "jon@example.com"},
+			{ID: 2, Email: "arya@example.com"},
+			{ID: 3, Email: "sansa@example.com"},
+		},
+	})
+	return srv, srv.Client()
+}
+
+// tagged returns the IDs of the subscribers with tag 14.
+func tagged(srv *convertkittest.Server) string {
+	var ids []int
+	for _, sub := range srv.Subscribers() {
+		for _, tag := range srv.SubscriberTags(sub.ID) {
+			if tag == 14 {
+				ids = append(ids, int(sub.ID))
+			}
+		}
+	}
+	sort.Ints(ids)
+	return fmt.Sprint(ids)
+}
+
+func TestClient_BulkTag(t *testing.T) {
+	srv, c := bulkServer(t)
+	targets := append(convertkit.BulkEmails("jon@example.com"), convertkit.BulkSubscriberIDs(2, 999)...)
+	results := make(map[int]convertkit.BulkResult)
+	sum, err := c.BulkTag(context.Background(), 14, targets, convertkit.BulkOptions{
+		OnResult: func(res convertkit.BulkResult) {
+			results[res.Index] = res
+		},
+	})
+	if err != nil {
+		t.Fatalf("BulkTag() err = %v; want nil", err)
+	}
+	want := convertkit.BulkSummary{Total: 3, Succeeded: 2, Failed: 1, Completed: 3}
+	if *sum != want {
+		t.Errorf("BulkTag() = %+v; want %+v", *sum, want)
+	}
+	if got := tagged(srv); got != "[1 2]" {
+		t.Errorf("tagged = %v; want [1 2]", got)
+	}
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d; want 3", len(results))
+	}
+	if results[0].SubscriberID != 1 || results[0].Err != nil {
+		t.Errorf("results[0] = %+v; want subscriber 1 without an error", results[0])
+	}
+	if results[2].SubscriberID != 999 || results[2].Err == nil {
+		t.Errorf("results[2] = %+v; want subscriber 999 with an error", results[2])
+	}
+}
+
+func TestClient_BulkUntag(t *testing.T) {
+	srv, c := bulkServer(t)
+	_, err := c.BulkTag(context.Background(), 14, convertkit.BulkSubscriberIDs(1, 2, 3), convertkit.BulkOptions{})
+	if err != nil {
+		t.Fatalf("BulkTag() err = %v; want nil", err)
+	}
+	targets := append(convertkit.BulkEmails("jon@example.com", "nobody@example.com"), convertkit.BulkSubscriberIDs(3)...)
+	var failed convertkit.BulkResult
+	sum, err := c.BulkUntag(context.Background(), 14, targets, convertkit.BulkOptions{
+		OnResult: func(res convertkit.BulkResult) {
+			if res.Err != nil {
+				failed = res
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("BulkUntag() err = %v; want nil", err)
+	}
+	want := convertkit.BulkSummary{Total: 3, Succeeded: 2, Failed: 1, Completed: 3}
+	if *sum != want {
+		t.Errorf("BulkUntag() = %+v; want %+v", *sum, want)
+	}
+	if got := tagged(srv); got != "[2]" {
+		t.Errorf("tagged = %v; want [2]", got)
+	}
+	wantErr := `no subscriber with email address "nobody@example.com"`
+	if failed.Err == nil || failed.Err.Error() != wantErr {
+		t.Errorf("failed.Err = %v; want %v", failed.Err, wantErr)
+	}
+}
+
+// cancelClient calls cancel after sending a request with the given method.
+type cancelClient struct {
+	convertkittest.HTTPClient
+	method string
+	cancel func()
+}
+
+func (cc *cancelClient) Do(r *http.Request) (*http.Response, error) {
+	resp, err := cc.HTTPClient.Do(r)
+	if r.Method == cc.method {
+		cc.cancel()
+	}
+	return resp, err
+}
+
+func TestClient_BulkTag_resume(t *testing.T) {
+	srv, c := bulkServer(t)
+	targets := convertkit.BulkSubscriberIDs(1, 2, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	// Cancel once the first subscriber has been tagged.
+	c.HTTPClient = &cancelClient{HTTPClient: c.HTTPClient, method: http.MethodPost, cancel: cancel}
+	sum, err := c.BulkTag(ctx, 14, targets, convertkit.BulkOptions{Workers: 1})
+	if err != context.Canceled {
+		t.Fatalf("BulkTag() err = %v; want %v", err, context.Canceled)
+	}
+	if sum.Completed != 1 || sum.Succeeded != 1 {
+		t.Errorf("BulkTag() = %+v; want 1 completed and succeeded", *sum)
+	}
+	if got := tagged(srv); got != "[1]" {
+		t.Errorf("tagged = %v; want [1]", got)
+	}
+
+	c = srv.Client()
+	sum, err = c.BulkTag(context.Background(), 14, targets, convertkit.BulkOptions{Skip: sum.Completed})
+	if err != nil {
+		t.Fatalf("BulkTag() err = %v; want nil", err)
+	}
+	want := convertkit.BulkSummary{Total: 3, Succeeded: 2, Skipped: 1, Completed: 3}
+	if *sum != want {
+		t.Errorf("BulkTag() = %+v; want %+v", *sum, want)
+	}
+	if got := tagged(srv); got != "[1 2 3]" {
+		t.Errorf("tagged = %v; want [1 2 3]", got)
+	}
+}
+
+func TestClient_BulkTag_cancelledAfterFinishing(t *testing.T) {
+	srv, c := bulkServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel once the only target has been tagged.
+	c.HTTPClient = &cancelClient{HTTPClient: c.HTTPClient, method: http.MethodPost, cancel: cancel}
+	sum, err := c.BulkTag(ctx, 14, convertkit.BulkEmails("jon@example.com"), convertkit.BulkOptions{})
+	if err != nil {
+		t.Fatalf("BulkTag() err = %v; want nil", err)
+	}
+	want := convertkit.BulkSummary{Total: 1, Succeeded: 1, Completed: 1}
+	if *sum != want {
+		t.Errorf("BulkTag() = %+v; want %+v", *sum, want)
+	}
+	if got := tagged(srv); got != "[1]" {
+		t.Errorf("tagged = %v; want [1]", got)
+	}
+}
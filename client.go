@@ -9,6 +9,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Default values
@@ -23,10 +26,44 @@ type Client struct {
 	HTTPClient interface {
 		Do(*http.Request) (*http.Response, error)
 	}
+	// CaptureRawJSON will populate the RawJSON embedded in every response type
+	// with the original JSON and any fields this package doesn't know about.
+	CaptureRawJSON bool
+	// OnSchemaDrift, if set, is called whenever a response doesn't match the
+	// types in this package. This includes unknown fields, missing fields, and
+	// type mismatches. Drift never causes a call to fail: fields with a
+	// mismatched type are left as their zero value and everything else is
+	// decoded as usual.
+	OnSchemaDrift func(method, path string, drifts []SchemaDrift)
+
+	// shared holds a *clientState. It is kept behind a pointer so that copies
+	// of a Client share it and can be made without tripping go vet.
+	shared atomic.Value
+}
+
+// clientState is the state shared by a Client's calls, created on first use.
+type clientState struct {
+	// ensureTagsMu serializes EnsureTags calls.
+	ensureTagsMu sync.Mutex
+	names        nameCache
+	limiter      *RateLimiter
+}
+
+func (c *Client) state() *clientState {
+	if s, ok := c.shared.Load().(*clientState); ok {
+		return s
+	}
+	c.shared.CompareAndSwap(nil, &clientState{
+		limiter: NewRateLimiter(DefaultRateLimit, time.Minute),
+	})
+	return c.shared.Load().(*clientState)
 }
 
 // Do will perform any API query by:
 //
+// 0. Validating the params if they implement a Validate() error method. If
+// validation fails a ValidationError is returned and no HTTP request is made.
+//
 // 1. Adding the API Secret wherever it is needed.
 //
 // 2. Encoding the params.
@@ -50,13 +87,19 @@ func (c *Client) Do(method, path string, params, response interface{}) error {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
+	if v, ok := params.(validatable); ok {
+		err := v.Validate()
+		if err != nil {
+			return err
+		}
+	}
 	req, err := c.request(method, path, params)
 	if err != nil {
 		return err
 	}
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return err
+		return c.redact(err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode > 400 {
@@ -69,13 +112,74 @@ func (c *Client) Do(method, path string, params, response interface{}) error {
 		}
 		return c.decodeError(resp)
 	}
-	err = c.decode(resp.Body, response)
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+	err = c.decode(bytes.NewReader(b), response)
+	if c.OnSchemaDrift != nil {
+		drifts := c.reportSchemaDrift(method, path, b, response)
+		if err != nil && decodeSkippingMismatches(b, response, drifts) == nil {
+			if c.CaptureRawJSON {
+				captureRawJSON(b, response)
+			}
+			err = nil
+		}
+	}
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// redact removes the API secret from errors returned by the HTTPClient. These
+// usually include the request URL, which has the secret in its query params
+// for GET and DELETE requests.
+func (c *Client) redact(err error) error {
+	if c.Secret == "" {
+		return err
+	}
+	replacer := strings.NewReplacer(url.QueryEscape(c.Secret), "REDACTED", c.Secret, "REDACTED")
+	hasSecret := func(s string) bool {
+		return replacer.Replace(s) != s
+	}
+	if !hasSecret(err.Error()) {
+		return err
+	}
+	// Keep *url.Error errors as-is where possible so that callers can still
+	// check things like Timeout().
+	if urlErr, ok := err.(*url.Error); ok && !hasSecret(urlErr.Err.Error()) {
+		return &url.Error{Op: urlErr.Op, URL: replacer.Replace(urlErr.URL), Err: urlErr.Err}
+	}
+	return redactedError{msg: replacer.Replace(err.Error()), err: err}
+}
+
+// redactedError is an error with the API secret removed from its message.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e redactedError) Error() string {
+	return e.msg
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
+
+func (c *Client) reportSchemaDrift(method, path string, b []byte, response interface{}) []SchemaDrift {
+	if !json.Valid(b) {
+		return nil
+	}
+	drifts := schemaDrift(b, response)
+	if len(drifts) == 0 {
+		return nil
+	}
+	c.OnSchemaDrift(method, path, drifts)
+	return drifts
+}
+
 func (c *Client) request(method, path string, params interface{}) (*http.Request, error) {
 	var reqURL string
 	var reqBody io.Reader
@@ -124,8 +228,12 @@ func jsonMap(params interface{}) (map[string]interface{}, error) {
 	if err != nil {
 		return nil, fmt.Errorf("json map: %w", err)
 	}
+	// Numbers are kept as json.Number so that large integers don't lose
+	// precision or get formatted like 1e+07 in query params.
 	var m map[string]interface{}
-	err = json.NewDecoder(&buffer).Decode(&m)
+	dec := json.NewDecoder(&buffer)
+	dec.UseNumber()
+	err = dec.Decode(&m)
 	if err != nil {
 		return nil, fmt.Errorf("json map: %w", err)
 	}
@@ -171,6 +279,9 @@ func (c *Client) decode(r io.Reader, v interface{}) error {
 	if err != nil {
 		return fmt.Errorf("decoding: %w", err)
 	}
+	if c.CaptureRawJSON {
+		captureRawJSON(b, v)
+	}
 	return nil
 }
 
@@ -11,10 +11,13 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
 )
 
 func TestClient_AuthError(t *testing.T) {
@@ -162,3 +165,86 @@ func body(t *testing.T, path string) io.ReadCloser {
 	}
 	return f
 }
+
+func TestClient_DoPages(t *testing.T) {
+	srv := convertkittest.NewServer("fake-secret-key")
+	defer srv.Close()
+	var subs []convertkit.Subscriber
+	for i := 0; i < 2*convertkittest.PageSize+1; i++ {
+		subs = append(subs, convertkit.Subscriber{Email: fmt.Sprintf("%d@example.com", i)})
+	}
+	srv.Seed(convertkittest.Fixtures{Subscribers: subs})
+	c := srv.Client()
+
+	tests := map[string]struct {
+		params map[string]interface{}
+		want   []int
+	}{
+		"all pages":         {want: []int{1, 2, 3}},
+		"from a later page": {params: map[string]interface{}{"page": 2}, want: []int{2, 3}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []int
+			err := c.DoPages(http.MethodGet, "subscribers", tc.params, func(raw json.RawMessage) error {
+				var page convertkit.SubscribersResponse
+				err := json.Unmarshal(raw, &page)
+				if err != nil {
+					return err
+				}
+				got = append(got, page.Page)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("DoPages() err = %v; want nil", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("pages = %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("not paginated", func(t *testing.T) {
+		calls := 0
+		err := c.DoPages(http.MethodGet, "tags", nil, func(json.RawMessage) error {
+			calls++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("DoPages() err = %v; want nil", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d; want 1", calls)
+		}
+	})
+
+	t.Run("page ignored", func(t *testing.T) {
+		c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"page": 1, "total_pages": 3}`))
+		})
+		err := c.DoPages(http.MethodGet, "subscribers", nil, func(json.RawMessage) error { return nil })
+		if err == nil {
+			t.Errorf("DoPages() err = nil; want an error")
+		}
+	})
+}
+
+func TestClient_Do_redactsSecret(t *testing.T) {
+	c := &convertkit.Client{
+		Secret:  "super/secret",
+		BaseURL: "http://convertkit.test",
+		HTTPClient: &convertkittest.FaultInjector{
+			Script: []convertkittest.Fault{{Kind: convertkittest.FaultReset}},
+		},
+	}
+	_, err := c.Tags()
+	if err == nil {
+		t.Fatalf("Tags() err = nil; want an error")
+	}
+	if strings.Contains(err.Error(), "super") {
+		t.Errorf("Tags() err = %v; want the secret to be redacted", err)
+	}
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Tags() err = %v; want it to wrap %v", err, syscall.ECONNRESET)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// paramsFlag collects repeated -f KEY=VALUE flags.
+type paramsFlag map[string]interface{}
+
+func (f paramsFlag) String() string {
+	return fmt.Sprint(map[string]interface{}(f))
+}
+
+func (f paramsFlag) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return fmt.Errorf("want KEY=VALUE, got %q", s)
+	}
+	f[s[:i]] = s[i+1:]
+	return nil
+}
+
+// apiCmd calls any endpoint with Client.Do, so the secret is added for the
+// user and never ends up in their shell history.
+func apiCmd(a *app, args []string) error {
+	fields := make(paramsFlag)
+	var data string
+	var paginate bool
+	fs := a.flagSet("api")
+	fs.Var(fields, "f", "param to send as KEY=VALUE (repeatable)")
+	fs.StringVar(&data, "data", "", "JSON object to send as params, or @FILE to read it from a file (@- for stdin)")
+	fs.BoolVar(&paginate, "paginate", false, "fetch every page of a response with page and total_pages fields")
+	rest, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 2 {
+		return usagef("a method and a path are required")
+	}
+	method := strings.ToUpper(rest[0])
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return usagef("unsupported method %q", rest[0])
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(rest[1], "/"), "v3/")
+
+	params, err := a.readData(data)
+	if err != nil {
+		return err
+	}
+	for k, v := range fields {
+		params[k] = v
+	}
+
+	if method != http.MethodGet {
+		a.cmd.destructive = true
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	printPage := func(raw json.RawMessage) error {
+		var buf bytes.Buffer
+		err := json.Indent(&buf, raw, "", "  ")
+		if err != nil {
+			return fmt.Errorf("formatting response: %w", err)
+		}
+		buf.WriteByte('\n')
+		out := strings.ReplaceAll(buf.String(), c.Secret, "REDACTED")
+		_, err = fmt.Fprint(a.stdout, out)
+		return err
+	}
+	if paginate {
+		return c.DoPages(method, path, params, printPage)
+	}
+	var raw json.RawMessage
+	err = c.Do(method, path, params, &raw)
+	if err != nil {
+		return err
+	}
+	return printPage(raw)
+}
+
+// readData reads the --data flag.
+func (a *app) readData(data string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if data == "" {
+		return params, nil
+	}
+	b := []byte(data)
+	if strings.HasPrefix(data, "@") {
+		var err error
+		if data == "@-" {
+			b, err = ioutil.ReadAll(a.stdin)
+		} else {
+			b, err = ioutil.ReadFile(data[1:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading --data: %w", err)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err := dec.Decode(&params)
+	if err != nil {
+		return nil, usagef("--data must be a JSON object: %v", err)
+	}
+	return params, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func TestRun_api(t *testing.T) {
+	srv := server(t)
+	dataFile := filepath.Join(t.TempDir(), "tag.json")
+	err := ioutil.WriteFile(dataFile, []byte(`{"tag": {"name": "From File"}}`), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	tests := map[string]struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		"get": {
+			args:   []string{"api", "GET", "/v3/tags"},
+			stdout: "{\n  \"tags\": [\n    {\n      \"id\": 14,\n      \"name\": \"Customer\",",
+		},
+		"fields": {
+			args:   []string{"api", "get", "subscribers", "-f", "email_address=arya@example.com"},
+			stdout: `"total_subscribers": 1,`,
+		},
+		"data": {
+			args:   []string{"api", "POST", "tags", "-data", `{"tag": {"name": "Inline"}}`},
+			stdout: `"name": "Inline"`,
+		},
+		"data file": {
+			args:   []string{"api", "POST", "tags", "-data", "@" + dataFile},
+			stdout: `"name": "From File"`,
+		},
+		"api error": {
+			args:   []string{"api", "GET", "subscribers/999"},
+			code:   exitError,
+			stderr: "404 - not_found_error",
+		},
+		"bad data": {
+			args:   []string{"api", "POST", "tags", "-data", `["not", "an", "object"]`},
+			code:   exitUsage,
+			stderr: "--data must be a JSON object",
+		},
+		"bad method": {
+			args:   []string{"api", "FETCH", "tags"},
+			code:   exitUsage,
+			stderr: `unsupported method "FETCH"`,
+		},
+		"missing path": {
+			args:   []string{"api", "GET"},
+			code:   exitUsage,
+			stderr: "a method and a path are required",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, stdout, stderr := cli(t, srv, tc.args...)
+			if code != tc.code {
+				t.Errorf("exit code = %d; want %d\nstderr: %s", code, tc.code, stderr)
+			}
+			if !strings.Contains(stdout, tc.stdout) {
+				t.Errorf("stdout = %q; want it to contain %q", stdout, tc.stdout)
+			}
+			if !strings.Contains(stderr, tc.stderr) {
+				t.Errorf("stderr = %q; want it to contain %q", stderr, tc.stderr)
+			}
+		})
+	}
+}
+
+func TestRun_apiPaginate(t *testing.T) {
+	srv := convertkittest.NewServer("secret")
+	defer srv.Close()
+	var subs []convertkit.Subscriber
+	for i := 0; i < convertkittest.PageSize+1; i++ {
+		subs = append(subs, convertkit.Subscriber{Email: "x@example.com"})
+	}
+	srv.Seed(convertkittest.Fixtures{Subscribers: subs})
+
+	code, stdout, stderr := cli(t, srv, "api", "GET", "subscribers", "-paginate")
+	if code != exitOK {
+		t.Fatalf("exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	dec := json.NewDecoder(strings.NewReader(stdout))
+	var pages []int
+	for dec.More() {
+		var page convertkit.SubscribersResponse
+		err := dec.Decode(&page)
+		if err != nil {
+			t.Fatalf("Decode() err = %v; want nil", err)
+		}
+		pages = append(pages, page.Page)
+	}
+	if len(pages) != 2 || pages[0] != 1 || pages[1] != 2 {
+		t.Errorf("pages = %v; want [1 2]", pages)
+	}
+}
+
+func TestRun_apiProduction(t *testing.T) {
+	srv := server(t)
+	env := map[string]string{envConfig: writeConfig(t, srv)}
+	code, _, stderr := runWith(env, "", "-profile", "production", "api", "POST", "tags", "-f", "name=x")
+	if code != exitError || !strings.Contains(stderr, "aborted") {
+		t.Errorf("POST exit code = %d, stderr = %q; want confirmation to be required", code, stderr)
+	}
+	code, _, stderr = runWith(env, "", "-profile", "production", "api", "GET", "tags")
+	if code != exitOK {
+		t.Errorf("GET exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// fieldsFlag collects repeated -field KEY=VALUE flags.
+type fieldsFlag map[string]string
+
+func (f fieldsFlag) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f fieldsFlag) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return fmt.Errorf("want KEY=VALUE, got %q", s)
+	}
+	f[s[:i]] = s[i+1:]
+	return nil
+}
+
+// tagsFlag collects repeated -tag ID flags.
+type tagsFlag []convertkit.TagID
+
+func (f *tagsFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *tagsFlag) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid tag ID %q", s)
+	}
+	*f = append(*f, convertkit.TagID(n))
+	return nil
+}
+
+// dateFlag parses a yyyy-mm-dd flag.
+type dateFlag struct {
+	date *convertkit.Date
+}
+
+func (f *dateFlag) String() string {
+	if f.date == nil {
+		return ""
+	}
+	return time.Time(*f.date).Format("2006-01-02")
+}
+
+func (f *dateFlag) Set(s string) error {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return fmt.Errorf("want yyyy-mm-dd, got %q", s)
+	}
+	d := convertkit.Date(t)
+	f.date = &d
+	return nil
+}
+
+func parseID(name, s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, usagef("invalid %s %q", name, s)
+	}
+	return n, nil
+}
+
+// noArgs parses a command that only accepts flags.
+func (a *app) noArgs(name string, args []string) error {
+	fs := a.flagSet(name)
+	rest, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	return nil
+}
+
+func accountCmd(a *app, args []string) error {
+	err := a.noArgs("account", args)
+	if err != nil {
+		return err
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Account()
+	if err != nil {
+		return err
+	}
+	t := table{header: []string{"name", "primary_email_address"}}
+	t.add(resp.Name, resp.PrimaryEmail)
+	return a.print(resp, t)
+}
+
+func formsListCmd(a *app, args []string) error {
+	err := a.noArgs("forms list", args)
+	if err != nil {
+		return err
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Forms()
+	if err != nil {
+		return err
+	}
+	t := table{header: []string{"id", "name", "type", "created_at"}}
+	for _, f := range resp.Forms {
+		t.add(id(f.ID), f.Name, f.Type, timestamp(f.CreatedAt))
+	}
+	return a.print(resp.Forms, t)
+}
+
+func sequencesListCmd(a *app, args []string) error {
+	err := a.noArgs("sequences list", args)
+	if err != nil {
+		return err
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Sequences()
+	if err != nil {
+		return err
+	}
+	t := table{header: []string{"id", "name", "created_at"}}
+	for _, s := range resp.Sequences {
+		t.add(id(s.ID), s.Name, timestamp(s.CreatedAt))
+	}
+	return a.print(resp.Sequences, t)
+}
+
+func tagsListCmd(a *app, args []string) error {
+	err := a.noArgs("tags list", args)
+	if err != nil {
+		return err
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Tags()
+	if err != nil {
+		return err
+	}
+	return a.print(resp.Tags, tagTable(resp.Tags))
+}
+
+func tagsCreateCmd(a *app, args []string) error {
+	names, err := parse(a.flagSet("tags create"), args)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return usagef("at least one tag name is required")
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.CreateTags(names...)
+	if err != nil {
+		return err
+	}
+	return a.print(resp.Tags, tagTable(resp.Tags))
+}
+
+func subscribersListCmd(a *app, args []string) error {
+	var req convertkit.SubscribersRequest
+	var all bool
+	var from, to dateFlag
+	var sortOrder string
+	fs := a.flagSet("subscribers list")
+	fs.IntVar(&req.Page, "page", 0, "page of results to show")
+	fs.BoolVar(&all, "all", false, "show every page of results")
+	fs.StringVar(&req.Email, "email", "", "only show the subscriber with this email address")
+	fs.Var(&from, "from", "only show subscribers created on or after this date (yyyy-mm-dd)")
+	fs.Var(&to, "to", "only show subscribers created on or before this date (yyyy-mm-dd)")
+	fs.StringVar(&sortOrder, "sort", "", "sort order: asc or desc")
+	rest, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	if all && req.Page != 0 {
+		return usagef("-all and -page can't be used together")
+	}
+	req.From, req.To = from.date, to.date
+	req.SortOrder = convertkit.SortOrder(sortOrder)
+
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	subs := []convertkit.Subscriber{}
+	for {
+		resp, err := c.Subscribers(req)
+		if err != nil {
+			return err
+		}
+		subs = append(subs, resp.Subscribers...)
+		if !all || resp.Page >= resp.TotalPages {
+			break
+		}
+		req.Page = resp.Page + 1
+	}
+	return a.print(subs, subscriberTable(subs))
+}
+
+func subscribersGetCmd(a *app, args []string) error {
+	rest, err := parse(a.flagSet("subscribers get"), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("exactly one subscriber ID is required")
+	}
+	subID, err := parseID("subscriber ID", rest[0])
+	if err != nil {
+		return err
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Subscriber(convertkit.SubscriberID(subID))
+	if err != nil {
+		return err
+	}
+	return a.print(resp.Subscriber, subscriberTable([]convertkit.Subscriber{resp.Subscriber}))
+}
+
+func subscribersUpdateCmd(a *app, args []string) error {
+	var req convertkit.UpdateSubscriberRequest
+	fields := make(fieldsFlag)
+	fs := a.flagSet("subscribers update")
+	fs.StringVar(&req.FirstName, "first-name", "", "new first name")
+	fs.StringVar(&req.Email, "email", "", "new email address")
+	fs.Var(fields, "field", "custom field to set as KEY=VALUE (repeatable)")
+	rest, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("exactly one subscriber ID is required")
+	}
+	subID, err := parseID("subscriber ID", rest[0])
+	if err != nil {
+		return err
+	}
+	req.SubscriberID = convertkit.SubscriberID(subID)
+	if len(fields) > 0 {
+		req.Fields = fields
+	}
+	if req.FirstName == "" && req.Email == "" && req.Fields == nil {
+		return usagef("nothing to update; use -first-name, -email, or -field")
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.UpdateSubscriber(req)
+	if err != nil {
+		return err
+	}
+	return a.print(resp.Subscriber, subscriberTable([]convertkit.Subscriber{resp.Subscriber}))
+}
+
+func subscribersUnsubscribeCmd(a *app, args []string) error {
+	rest, err := parse(a.flagSet("subscribers unsubscribe"), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("exactly one email address is required")
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.UnsubscribeSubscriber(rest[0])
+	if err != nil {
+		return err
+	}
+	return a.print(resp.Subscriber, subscriberTable([]convertkit.Subscriber{resp.Subscriber}))
+}
+
+// subscribeCmd returns the command for subscribing to a form, sequence, or
+// tag.
+func subscribeCmd(kind string) func(a *app, args []string) error {
+	return func(a *app, args []string) error {
+		var email, firstName string
+		fields := make(fieldsFlag)
+		var tags tagsFlag
+		fs := a.flagSet("subscribe " + kind)
+		fs.StringVar(&email, "email", "", "email address to subscribe (required)")
+		fs.StringVar(&firstName, "first-name", "", "first name of the subscriber")
+		fs.Var(fields, "field", "custom field to set as KEY=VALUE (repeatable)")
+		fs.Var(&tags, "tag", "additional tag ID to apply (repeatable)")
+		rest, err := parse(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(rest) != 1 {
+			return usagef("exactly one %s ID is required", kind)
+		}
+		n, err := parseID(kind+" ID", rest[0])
+		if err != nil {
+			return err
+		}
+		if email == "" {
+			return usagef("-email is required")
+		}
+		if len(fields) == 0 {
+			fields = nil
+		}
+		c, err := a.apiClient()
+		if err != nil {
+			return err
+		}
+
+		var sub convertkit.Subscription
+		switch kind {
+		case "form":
+			resp, err := c.SubscribeToForm(convertkit.SubscribeToFormRequest{
+				FormID: convertkit.FormID(n), Email: email, FirstName: firstName, Fields: fields, TagIDs: tags,
+			})
+			if err != nil {
+				return err
+			}
+			sub = resp.Subscription
+		case "sequence":
+			resp, err := c.SubscribeToSequence(convertkit.SubscribeToSequenceRequest{
+				SequenceID: convertkit.SequenceID(n), Email: email, FirstName: firstName, Fields: fields, TagIDs: tags,
+			})
+			if err != nil {
+				return err
+			}
+			sub = resp.Subscription
+		case "tag":
+			resp, err := c.TagSubscriber(convertkit.TagSubscriberRequest{
+				TagID: convertkit.TagID(n), Email: email, FirstName: firstName, Fields: fields, TagIDs: tags,
+			})
+			if err != nil {
+				return err
+			}
+			sub = resp.Subscription
+		}
+		return a.print(sub, subscriptionTable([]convertkit.Subscription{sub}))
+	}
+}
+
+func untagCmd(a *app, args []string) error {
+	rest, err := parse(a.flagSet("untag"), args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 2 {
+		return usagef("a subscriber ID and a tag ID are required")
+	}
+	subID, err := parseID("subscriber ID", rest[0])
+	if err != nil {
+		return err
+	}
+	tagID, err := parseID("tag ID", rest[1])
+	if err != nil {
+		return err
+	}
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	resp, err := c.UntagSubscriber(convertkit.UntagSubscriberRequest{
+		SubscriberID: convertkit.SubscriberID(subID),
+		TagID:        convertkit.TagID(tagID),
+	})
+	if err != nil {
+		return err
+	}
+	return a.print(resp.Tag, tagTable([]convertkit.Tag{resp.Tag}))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/internal/atomicfile"
+)
+
+// namesFlag collects a repeated flag.
+type namesFlag []string
+
+func (f *namesFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *namesFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// exportCmd writes every subscriber to stdout or a file with
+// convertkit.Exporter.
+func exportCmd(a *app, args []string) error {
+	var e convertkit.Exporter
+	var format, out string
+	var fields namesFlag
+	var from, to dateFlag
+	fs := a.flagSet("export")
+	fs.StringVar(&format, "format", "csv", "file format: csv, jsonl, or json")
+	fs.StringVar(&out, "out", "", "file to write (default stdout)")
+	fs.Var(&fields, "field", "custom field to export (repeatable, default all)")
+	fs.BoolVar(&e.Tags, "tags", false, "include each subscriber's tags (one extra request per subscriber)")
+	fs.Var(&from, "from", "only export subscribers created on or after this date (yyyy-mm-dd)")
+	fs.Var(&to, "to", "only export subscribers created on or before this date (yyyy-mm-dd)")
+	rest, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	e.Format = convertkit.ExportFormat(format)
+	switch e.Format {
+	case convertkit.ExportCSV, convertkit.ExportJSONL, convertkit.ExportJSON:
+	default:
+		return usagef("unsupported format %q", format)
+	}
+	if len(fields) > 0 {
+		e.Fields = fields
+	}
+	e.Request.From, e.Request.To = from.date, to.date
+
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	e.Client = c
+	if out == "" {
+		_, err := e.Export(a.stdout)
+		return err
+	}
+	n, err := exportFile(&e, out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "exported %d subscribers to %s\n", n, out)
+	return nil
+}
+
+// exportFile exports to a temporary file that is renamed to path once the
+// export is complete, so a failed nightly export never replaces the previous
+// night's file with a partial one.
+func exportFile(e *convertkit.Exporter, path string) (int, error) {
+	var n int
+	err := atomicfile.Write(path, func(w io.Writer) error {
+		var err error
+		n, err = e.Export(w)
+		return err
+	})
+	return n, err
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_export(t *testing.T) {
+	srv := server(t)
+	tests := map[string]struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		"csv": {
+			args:   []string{"export"},
+			stdout: "id,email_address,first_name,state,created_at,fields.last_name\n1,jon@example.com,Jon,active,",
+		},
+		"jsonl with tags": {
+			args:   []string{"export", "-format", "jsonl", "-tags", "-field", "last_name"},
+			stdout: `"fields":{"last_name":""},"tags":[]}` + "\n",
+		},
+		"bad format": {
+			args:   []string{"export", "-format", "xml"},
+			code:   exitUsage,
+			stderr: `unsupported format "xml"`,
+		},
+		"extra args": {
+			args:   []string{"export", "everything"},
+			code:   exitUsage,
+			stderr: "unexpected arguments: everything",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, stdout, stderr := cli(t, srv, tc.args...)
+			if code != tc.code {
+				t.Errorf("exit code = %d; want %d\nstderr: %s", code, tc.code, stderr)
+			}
+			if !strings.Contains(stdout, tc.stdout) {
+				t.Errorf("stdout = %q; want it to contain %q", stdout, tc.stdout)
+			}
+			if !strings.Contains(stderr, tc.stderr) {
+				t.Errorf("stderr = %q; want it to contain %q", stderr, tc.stderr)
+			}
+		})
+	}
+}
+
+func TestRun_exportFile(t *testing.T) {
+	srv := server(t)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "subscribers.json")
+	code, _, stderr := cli(t, srv, "export", "-format", "json", "-out", out)
+	if code != exitOK {
+		t.Fatalf("exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	if want := "exported 2 subscribers to " + out; !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q; want it to contain %q", stderr, want)
+	}
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	if !strings.HasPrefix(string(b), "[\n  {\"id\":1,") {
+		t.Errorf("export = %q; want a JSON array", b)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() err = %v; want nil", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("len(files) = %d; want 1 (temporary file left behind?)", len(files))
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// importCmd subscribes every row of a file with convertkit.Importer.
+func importCmd(a *app, args []string) error {
+	var im convertkit.Importer
+	var format, errorsPath string
+	var formID, sequenceID, tagID, rate int
+	fields := make(fieldsFlag)
+	fs := a.flagSet("import")
+	fs.StringVar(&format, "format", "csv", "file format: csv or jsonl")
+	fs.IntVar(&formID, "form", 0, "subscribe rows to this form")
+	fs.IntVar(&sequenceID, "sequence", 0, "subscribe rows to this sequence")
+	fs.IntVar(&tagID, "tag", 0, "subscribe rows to this tag")
+	fs.StringVar(&im.Mapping.Email, "email-column", "", "column holding email addresses (default email_address)")
+	fs.StringVar(&im.Mapping.FirstName, "first-name-column", "", "column holding first names (default first_name)")
+	fs.Var(fields, "field", "custom field to import as KEY=COLUMN (repeatable, default every fields.KEY column)")
+	fs.StringVar(&im.Mapping.Tags, "tags-column", "", "column holding tag names or IDs (default tags)")
+	fs.StringVar(&im.Mapping.TagSeparator, "tag-separator", "", "separator between tags (default ;)")
+	fs.IntVar(&im.Workers, "workers", 4, "number of concurrent requests")
+	fs.IntVar(&rate, "rate", convertkit.DefaultRateLimit, "maximum requests per minute")
+	fs.BoolVar(&im.DryRun, "dry-run", false, "check every row without subscribing anyone")
+	fs.StringVar(&im.Checkpoint, "checkpoint", "", "file to save progress to, so an interrupted import can be resumed")
+	fs.StringVar(&errorsPath, "errors", "", "CSV file to write failed rows to (default stderr)")
+	rest, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usagef("exactly one file is required (use - for stdin)")
+	}
+	im.Format = convertkit.ImportFormat(format)
+	switch im.Format {
+	case convertkit.ImportCSV, convertkit.ImportJSONL:
+	default:
+		return usagef("unsupported format %q", format)
+	}
+	im.Target = convertkit.ImportTarget{
+		FormID:     convertkit.FormID(formID),
+		SequenceID: convertkit.SequenceID(sequenceID),
+		TagID:      convertkit.TagID(tagID),
+	}
+	if im.Target.Validate() != nil {
+		return usagef("exactly one of -form, -sequence or -tag is required")
+	}
+	if rate <= 0 {
+		return usagef("-rate must be positive")
+	}
+	im.Limiter = convertkit.NewRateLimiter(rate, time.Minute)
+	if len(fields) > 0 {
+		im.Mapping.Fields = fields
+	}
+
+	var in io.Reader = a.stdin
+	if rest[0] != "-" {
+		f, err := os.Open(rest[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	im.Errors = a.stderr
+	if errorsPath != "" {
+		f, err := openErrorsFile(errorsPath, im.Checkpoint)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		im.Errors = f
+	}
+
+	a.cmd.destructive = !im.DryRun
+	c, err := a.apiClient()
+	if err != nil {
+		return err
+	}
+	im.Client = c
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	sum, err := im.Import(ctx, in)
+	if sum != nil {
+		fmt.Fprintln(a.stdout, sum)
+	}
+	if err == context.Canceled && im.Checkpoint != "" {
+		return fmt.Errorf("interrupted; run the same command again to resume")
+	}
+	return err
+}
+
+// openErrorsFile opens the errors file for writing. When resuming from a
+// checkpoint it is appended to so that failures from the earlier run are kept.
+func openErrorsFile(path, checkpoint string) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if checkpoint != "" {
+		if _, err := os.Stat(checkpoint); err == nil {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+	}
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("opening errors file: %w", err)
+	}
+	return f, nil
+}
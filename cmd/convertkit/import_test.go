@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_import(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "people.csv")
+	err := ioutil.WriteFile(file, []byte("Email,Company,tags\nsansa@example.com,Winterfell,Customer\nbran@example.com,,Nope\n"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	tests := map[string]struct {
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+		// subscribers is the number of subscribers after the import.
+		subscribers int
+	}{
+		"file": {
+			args:        []string{"import", "-form", "213", "-email-column", "Email", "-field", "company=Company", file},
+			stdout:      "2 rows: 1 imported, 1 failed, 0 skipped\n",
+			stderr:      "row,email_address,error\n2,bran@example.com,\"unknown tag \"\"Nope\"\"\"\n",
+			subscribers: 3,
+		},
+		"stdin": {
+			args:        []string{"import", "-tag", "14", "-format", "jsonl", "-"},
+			stdin:       `{"email_address": "rickon@example.com"}`,
+			stdout:      "1 rows: 1 imported, 0 failed, 0 skipped\n",
+			subscribers: 3,
+		},
+		"dry run": {
+			args:        []string{"import", "-form", "213", "-email-column", "Email", "-dry-run", file},
+			stdout:      "2 rows: 1 would be imported, 1 failed, 0 skipped\n",
+			subscribers: 2,
+		},
+		"no target": {
+			args:        []string{"import", file},
+			code:        exitUsage,
+			stderr:      "exactly one of -form, -sequence or -tag is required",
+			subscribers: 2,
+		},
+		"no file": {
+			args:        []string{"import", "-form", "213"},
+			code:        exitUsage,
+			stderr:      "exactly one file is required",
+			subscribers: 2,
+		},
+		"missing column": {
+			args:        []string{"import", "-form", "213", file},
+			code:        exitError,
+			stderr:      `missing column "email_address"`,
+			subscribers: 2,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := server(t)
+			env := map[string]string{
+				envSecret:  srv.Secret,
+				envBaseURL: srv.URL,
+			}
+			code, stdout, stderr := runWith(env, tc.stdin, tc.args...)
+			if code != tc.code {
+				t.Errorf("exit code = %d; want %d\nstderr: %s", code, tc.code, stderr)
+			}
+			if !strings.Contains(stdout, tc.stdout) {
+				t.Errorf("stdout = %q; want it to contain %q", stdout, tc.stdout)
+			}
+			if !strings.Contains(stderr, tc.stderr) {
+				t.Errorf("stderr = %q; want it to contain %q", stderr, tc.stderr)
+			}
+			if got := len(srv.Subscribers()); got != tc.subscribers {
+				t.Errorf("len(Subscribers()) = %d; want %d", got, tc.subscribers)
+			}
+		})
+	}
+}
+
+func TestRun_importResume(t *testing.T) {
+	srv := server(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "people.csv")
+	checkpoint := filepath.Join(dir, "people.checkpoint")
+	errorsFile := filepath.Join(dir, "people.errors.csv")
+	err := ioutil.WriteFile(file, []byte("email_address\nsansa@example.com\nnot-an-email\n"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	args := []string{"import", "-form", "213", "-checkpoint", checkpoint, "-errors", errorsFile, file}
+	code, _, stderr := cli(t, srv, args...)
+	if code != exitOK {
+		t.Fatalf("exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+
+	// Rows added to the file since the last run are picked up on the next
+	// one, and the errors file is appended to.
+	err = ioutil.WriteFile(file, []byte("email_address\nsansa@example.com\nnot-an-email\nalso-not-an-email\n"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	code, stdout, stderr := cli(t, srv, args...)
+	if code != exitOK {
+		t.Fatalf("exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	if want := "3 rows: 0 imported, 1 failed, 2 skipped\n"; stdout != want {
+		t.Errorf("stdout = %q; want %q", stdout, want)
+	}
+	b, err := ioutil.ReadFile(errorsFile)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 3 || lines[0] != "row,email_address,error" || !strings.HasPrefix(lines[2], "3,also-not-an-email,") {
+		t.Errorf("errors file = %q; want a header and rows 2 and 3", b)
+	}
+}
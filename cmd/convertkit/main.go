@@ -0,0 +1,306 @@
+// Command convertkit is a command-line tool for inspecting and fixing data in
+// a Convert Kit account.
+//
+// Usage:
+//
+//	convertkit [global flags] <command> [flags] [args]
+//
+// The commands mirror the methods on convertkit.Client:
+//
+//	account
+//	forms list
+//	sequences list
+//	tags list
+//	tags create NAME...
+//	subscribers list [-page N | -all] [-email EMAIL] [-from DATE] [-to DATE] [-sort asc|desc]
+//	subscribers get ID
+//	subscribers update ID [-first-name NAME] [-email EMAIL] [-field KEY=VALUE]...
+//	subscribers unsubscribe EMAIL
+//	subscribe form|sequence|tag ID -email EMAIL [-first-name NAME] [-field KEY=VALUE]... [-tag ID]...
+//	untag SUBSCRIBER_ID TAG_ID
+//	export [-format csv|jsonl|json] [-out FILE] [-field KEY]... [-tags] [-from DATE] [-to DATE]
+//	import -form ID|-sequence ID|-tag ID [-format csv|jsonl] [-dry-run] [-checkpoint FILE] [-errors FILE] [flags] FILE
+//	profiles list
+//
+// Endpoints without a command can be called with:
+//
+//	convertkit api METHOD PATH [-f KEY=VALUE]... [-data JSON|@FILE] [-paginate]
+//
+// The API secret is read from the -secret flag, the CONVERTKIT_SECRET
+// environment variable, or a profile in the config file (see the config type).
+// Use -profile or CONVERTKIT_PROFILE to pick a profile other than the default.
+// Commands that change data ask for confirmation when run with a production
+// profile; -yes skips the prompt.
+//
+// Output is a table by default; use -o json or -o csv for something easier
+// to process.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// Environment variables
+const (
+	envSecret  = "CONVERTKIT_SECRET"
+	envBaseURL = "CONVERTKIT_BASE_URL"
+)
+
+// app holds the state shared by every command.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	secret      string
+	baseURL     string
+	format      string
+	configFile  string
+	profileName string
+	yes         bool
+
+	// cmd is the command being run.
+	cmd    command
+	client *convertkit.Client
+}
+
+// command is a single CLI command, eg "tags create".
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(a *app, args []string) error
+	// destructive commands change data, so they require confirmation when
+	// run against a production profile.
+	destructive bool
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"account", "", "Show the account", accountCmd, false},
+		{"forms list", "", "List forms", formsListCmd, false},
+		{"sequences list", "", "List sequences", sequencesListCmd, false},
+		{"tags list", "", "List tags", tagsListCmd, false},
+		{"tags create", "NAME...", "Create tags", tagsCreateCmd, true},
+		{"subscribers list", "[flags]", "List subscribers", subscribersListCmd, false},
+		{"subscribers get", "ID", "Show a subscriber", subscribersGetCmd, false},
+		{"subscribers update", "ID [flags]", "Update a subscriber", subscribersUpdateCmd, true},
+		{"subscribers unsubscribe", "EMAIL", "Unsubscribe an email address from everything", subscribersUnsubscribeCmd, true},
+		{"subscribe form", "ID -email EMAIL [flags]", "Subscribe an email address to a form", subscribeCmd("form"), true},
+		{"subscribe sequence", "ID -email EMAIL [flags]", "Subscribe an email address to a sequence", subscribeCmd("sequence"), true},
+		{"subscribe tag", "ID -email EMAIL [flags]", "Tag an email address", subscribeCmd("tag"), true},
+		{"untag", "SUBSCRIBER_ID TAG_ID", "Remove a tag from a subscriber", untagCmd, true},
+		{"export", "[-format csv|jsonl|json] [-out FILE] [flags]", "Export every subscriber", exportCmd, false},
+		{"import", "-form ID|-sequence ID|-tag ID [flags] FILE", "Subscribe every row of a CSV or JSON Lines file", importCmd, true},
+		{"profiles list", "", "List the profiles in the config file", profilesListCmd, false},
+		{"api", "METHOD PATH [-f KEY=VALUE]... [-data JSON|@FILE] [-paginate]", "Call any API endpoint", apiCmd, false},
+	}
+}
+
+// usageError is returned when a command is used incorrectly.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	a := &app{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		getenv: getenv,
+	}
+	fs := flag.NewFlagSet("convertkit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	a.globalFlags(fs)
+	fs.Usage = a.usage
+	err := fs.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		a.usage()
+		return exitUsage
+	}
+	cmd, args, ok := findCommand(args)
+	if !ok {
+		fmt.Fprintf(stderr, "convertkit: unknown command %q\n\n", strings.Join(args, " "))
+		a.usage()
+		return exitUsage
+	}
+	a.cmd = cmd
+	err = cmd.run(a, args)
+	var uerr usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &uerr):
+		fmt.Fprintf(stderr, "convertkit %s: %v\nusage: convertkit %s %s\n", cmd.name, err, cmd.name, cmd.args)
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "convertkit %s: %v\n", cmd.name, err)
+		return exitError
+	}
+}
+
+// findCommand returns the command named by the first one or two args, along
+// with the remaining args.
+func findCommand(args []string) (command, []string, bool) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[n:], true
+			}
+		}
+	}
+	return command{}, args, false
+}
+
+func (a *app) globalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&a.secret, "secret", "", "API secret (default $"+envSecret+" or the profile's secret)")
+	fs.StringVar(&a.baseURL, "base-url", "", "API base URL (default $"+envBaseURL+", the profile's base URL, or "+convertkit.DefaultBaseURL+")")
+	fs.StringVar(&a.format, "o", "", "output format: table, json, or csv (default table)")
+	fs.StringVar(&a.configFile, "config", "", "config file (default $"+envConfig+" or ~/.config/convertkit/config.json)")
+	fs.StringVar(&a.profileName, "profile", "", "profile to use (default $"+envProfile+" or the config's default_profile)")
+	fs.BoolVar(&a.yes, "yes", false, "don't ask for confirmation when changing production data")
+}
+
+func (a *app) usage() {
+	fmt.Fprintf(a.stderr, "usage: convertkit [global flags] <command> [flags] [args]\n\nCommands:\n")
+	sorted := append([]command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, cmd := range sorted {
+		fmt.Fprintf(a.stderr, "  %-24s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(a.stderr, "\nGlobal flags:\n")
+	fs := flag.NewFlagSet("convertkit", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.globalFlags(fs)
+	fs.PrintDefaults()
+}
+
+// flagSet returns a FlagSet for a command. The output format flag is
+// included so that it can be set before or after the command name.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("convertkit "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.format, "o", a.format, "output format: table, json, or csv (default table)")
+	return fs
+}
+
+// parse parses flags that may be mixed in with positional args, eg
+// "subscribe form 213 -email jon@example.com", and returns the positional
+// args.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// apiClient returns the client used by commands, creating it on first use.
+// Commands that change data must call it after parsing their args so that
+// confirmation is only requested for valid commands.
+func (a *app) apiClient() (*convertkit.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+	name, p, err := a.selectProfile()
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &profile{}
+	}
+	secret := a.secret
+	if secret == "" && name == "" {
+		secret = a.getenv(envSecret)
+	}
+	if secret == "" && name != "" {
+		secret, err = p.secret(a.getenv)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("no API secret: set %s, use -secret, or configure a profile", envSecret)
+	}
+	// Like the secret, the environment is ignored when a profile is used so
+	// that leftover variables from another session can't point a profile at
+	// the wrong account.
+	baseURL := a.baseURL
+	if baseURL == "" && name == "" {
+		baseURL = a.getenv(envBaseURL)
+	}
+	if baseURL == "" {
+		baseURL = p.BaseURL
+	}
+	if a.format == "" {
+		a.format = p.Output
+	}
+	// Check the format before making any calls so that a typo doesn't leave
+	// a change made but unreported.
+	err = checkFormat(a.format)
+	if err != nil {
+		return nil, err
+	}
+	if p.Production && a.cmd.destructive && !a.yes {
+		err := a.confirm(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	a.client = &convertkit.Client{
+		Secret:  secret,
+		BaseURL: baseURL,
+	}
+	return a.client, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+// cli runs the CLI against srv and returns the exit code, stdout, and stderr.
+func cli(t *testing.T, srv *convertkittest.Server, args ...string) (int, string, string) {
+	t.Helper()
+	env := map[string]string{
+		envSecret:  srv.Secret,
+		envBaseURL: srv.URL,
+	}
+	return runWith(env, "", args...)
+}
+
+// runWith runs the CLI with the given environment and stdin. The config file
+// defaults to one that doesn't exist so tests never read the real one.
+func runWith(env map[string]string, stdin string, args ...string) (int, string, string) {
+	getenv := func(k string) string {
+		if k == envConfig && env[k] == "" {
+			return "testdata/missing.json"
+		}
+		return env[k]
+	}
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr, getenv)
+	return code, stdout.String(), stderr.String()
+}
+
+func server(t *testing.T) *convertkittest.Server {
+	t.Helper()
+	srv := convertkittest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	srv.Seed(convertkittest.Fixtures{
+		Forms: []convertkit.Form{{ID: 213, Name: "Newsletter"}},
+		Tags:  []convertkit.Tag{{ID: 14, Name: "Customer"}},
+		Subscribers: []convertkit.Subscriber{
+			{ID: 1, Email: "jon@example.com", FirstName: "Jon", Fields: map[string]string{"last_name": "Snow"}},
+			{ID: 2, Email: "arya@example.com", FirstName: "Arya"},
+		},
+	})
+	return srv
+}
+
+func TestRun_output(t *testing.T) {
+	srv := server(t)
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"table": {
+			args: []string{"account"},
+			want: "NAME        PRIMARY EMAIL ADDRESS\nAcme Corp.  you@example.com\n",
+		},
+		"csv": {
+			args: []string{"-o", "csv", "subscribers", "list"},
+			want: "id,email_address,first_name,state,created_at,fields.last_name\n" +
+				"1,jon@example.com,Jon,active,{created},Snow\n" +
+				"2,arya@example.com,Arya,active,{created},\n",
+		},
+		"format after command": {
+			args: []string{"tags", "list", "-o", "csv"},
+			want: "id,name,created_at\n14,Customer,{created}\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, stdout, stderr := cli(t, srv, tc.args...)
+			if code != exitOK {
+				t.Fatalf("exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+			}
+			created := srv.Subscribers()[0].CreatedAt.Format("2006-01-02T15:04:05Z07:00")
+			want := strings.ReplaceAll(tc.want, "{created}", created)
+			if stdout != want {
+				t.Errorf("stdout = %q; want %q", stdout, want)
+			}
+		})
+	}
+}
+
+func TestRun_tags(t *testing.T) {
+	srv := server(t)
+	code, _, stderr := cli(t, srv, "tags", "create", "Lead", "Prospect")
+	if code != exitOK {
+		t.Fatalf("tags create exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	code, stdout, stderr := cli(t, srv, "-o", "json", "tags", "list")
+	if code != exitOK {
+		t.Fatalf("tags list exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	var tags []convertkit.Tag
+	err := json.Unmarshal([]byte(stdout), &tags)
+	if err != nil {
+		t.Fatalf("Unmarshal() err = %v; want nil", err)
+	}
+	if len(tags) != 3 || tags[2].Name != "Prospect" {
+		t.Errorf("tags = %+v; want Customer, Lead, and Prospect", tags)
+	}
+}
+
+func TestRun_subscribe(t *testing.T) {
+	srv := server(t)
+	code, _, stderr := cli(t, srv, "subscribe", "form", "213", "-email", "sansa@example.com", "-field", "house=Stark", "-tag", "14")
+	if code != exitOK {
+		t.Fatalf("subscribe exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	subs := srv.Subscribers()
+	sansa := subs[len(subs)-1]
+	if sansa.Email != "sansa@example.com" || sansa.Fields["house"] != "Stark" {
+		t.Errorf("subscriber = %+v; want sansa with house field", sansa)
+	}
+	if tags := srv.SubscriberTags(sansa.ID); len(tags) != 1 || tags[0] != 14 {
+		t.Errorf("SubscriberTags() = %v; want [14]", tags)
+	}
+
+	code, _, stderr = cli(t, srv, "untag", id(sansa.ID), "14")
+	if code != exitOK {
+		t.Fatalf("untag exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	if tags := srv.SubscriberTags(sansa.ID); len(tags) != 0 {
+		t.Errorf("SubscriberTags() = %v; want none", tags)
+	}
+
+	code, stdout, stderr := cli(t, srv, "-o", "json", "subscribers", "update", id(sansa.ID), "-first-name", "Sansa")
+	if code != exitOK {
+		t.Fatalf("update exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	if !strings.Contains(stdout, `"first_name": "Sansa"`) {
+		t.Errorf("stdout = %s; want updated first name", stdout)
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	srv := server(t)
+	tests := map[string]struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		"api error": {
+			args:   []string{"-secret", "wrong", "account"},
+			code:   exitError,
+			stderr: "convertkit account: 401 - Authorization Failed: API Key not valid\n",
+		},
+		"not found": {
+			args:   []string{"subscribers", "get", "999"},
+			code:   exitError,
+			stderr: "convertkit subscribers get: 404 - not_found_error",
+		},
+		"validation": {
+			args:   []string{"subscribe", "tag", "14", "-email", "not-an-email"},
+			code:   exitError,
+			stderr: "convertkit subscribe tag: invalid request: Email",
+		},
+		"missing argument": {
+			args:   []string{"subscribers", "get"},
+			code:   exitUsage,
+			stderr: "convertkit subscribers get: exactly one subscriber ID is required\nusage: convertkit subscribers get ID\n",
+		},
+		"unknown command": {
+			args:   []string{"broadcasts", "list"},
+			code:   exitUsage,
+			stderr: `convertkit: unknown command "broadcasts list"`,
+		},
+		"unknown format": {
+			args:   []string{"-o", "xml", "account"},
+			code:   exitUsage,
+			stderr: `unknown output format "xml"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, _, stderr := cli(t, srv, tc.args...)
+			if code != tc.code {
+				t.Errorf("exit code = %d; want %d", code, tc.code)
+			}
+			if !strings.Contains(stderr, tc.stderr) {
+				t.Errorf("stderr = %q; want it to contain %q", stderr, tc.stderr)
+			}
+		})
+	}
+}
+
+func TestRun_unknownFormat(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+	env := map[string]string{envSecret: "secret", envBaseURL: srv.URL}
+	code, _, stderr := runWith(env, "", "tags", "create", "Launch", "-o", "yaml")
+	if code != exitUsage {
+		t.Errorf("exit code = %d; want %d", code, exitUsage)
+	}
+	if want := `unknown output format "yaml"`; !strings.Contains(stderr, want) {
+		t.Errorf("stderr = %q; want it to contain %q", stderr, want)
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d; want 0", requests)
+	}
+}
+
+func TestRun_noSecret(t *testing.T) {
+	code, _, stderr := runWith(nil, "", "account")
+	if code != exitError {
+		t.Errorf("exit code = %d; want %d", code, exitError)
+	}
+	if !strings.Contains(stderr, "no API secret") {
+		t.Errorf("stderr = %q; want it to mention the missing secret", stderr)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// table is the tabular form of a command's output, used for the table and
+// CSV formats.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+// print writes v in the chosen output format. t is used for the table and CSV
+// formats, and v is encoded as-is for JSON.
+func (a *app) print(v interface{}, t table) error {
+	switch a.format {
+	case "json":
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "csv":
+		w := csv.NewWriter(a.stdout)
+		w.Write(t.header)
+		w.WriteAll(t.rows)
+		return w.Error()
+	case "table", "":
+		w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+		header := make([]string, len(t.header))
+		for i, h := range t.header {
+			header[i] = strings.ToUpper(strings.ReplaceAll(h, "_", " "))
+		}
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+	return checkFormat(a.format)
+}
+
+// checkFormat returns a usage error if format isn't a known output format.
+func checkFormat(format string) error {
+	switch format {
+	case "json", "csv", "table", "":
+		return nil
+	}
+	return usagef("unknown output format %q; want table, json, or csv", format)
+}
+
+func id(v interface{}) string {
+	return fmt.Sprintf("%d", v)
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func tagTable(tags []convertkit.Tag) table {
+	t := table{header: []string{"id", "name", "created_at"}}
+	for _, tag := range tags {
+		t.add(id(tag.ID), tag.Name, timestamp(tag.CreatedAt))
+	}
+	return t
+}
+
+// subscriberTable has a column for every custom field used by any of the
+// subscribers, in alphabetical order.
+func subscriberTable(subs []convertkit.Subscriber) table {
+	keys := make(map[string]bool)
+	for _, sub := range subs {
+		for k := range sub.Fields {
+			keys[k] = true
+		}
+	}
+	var fields []string
+	for k := range keys {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	t := table{header: []string{"id", "email_address", "first_name", "state", "created_at"}}
+	for _, f := range fields {
+		t.header = append(t.header, "fields."+f)
+	}
+	for _, sub := range subs {
+		row := []string{id(sub.ID), sub.Email, sub.FirstName, sub.State, timestamp(sub.CreatedAt)}
+		for _, f := range fields {
+			row = append(row, sub.Fields[f])
+		}
+		t.add(row...)
+	}
+	return t
+}
+
+func subscriptionTable(subs []convertkit.Subscription) table {
+	t := table{header: []string{"id", "state", "subscribable_type", "subscribable_id", "subscriber_id", "email_address", "created_at"}}
+	for _, sub := range subs {
+		t.add(strconv.Itoa(sub.ID), sub.State, sub.SubscribableType, strconv.Itoa(sub.SubscribableID),
+			id(sub.Subscriber.ID), sub.Subscriber.Email, timestamp(sub.CreatedAt))
+	}
+	return t
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Environment variables used for profiles.
+const (
+	envConfig  = "CONVERTKIT_CONFIG"
+	envProfile = "CONVERTKIT_PROFILE"
+)
+
+// config is the CLI's config file. By default it is stored at
+// ~/.config/convertkit/config.json (or the equivalent on other platforms):
+//
+//	{
+//	  "default_profile": "staging",
+//	  "profiles": {
+//	    "staging": {
+//	      "secret_file": "~/.convertkit/staging-secret"
+//	    },
+//	    "production": {
+//	      "secret_command": "pass show convertkit/production",
+//	      "production": true,
+//	      "output": "json"
+//	    }
+//	  }
+//	}
+type config struct {
+	DefaultProfile string              `json:"default_profile,omitempty"`
+	Profiles       map[string]*profile `json:"profiles"`
+}
+
+// profile is a named set of credentials and defaults.
+type profile struct {
+	// Only one of the Secret fields should be set. Secret is supported for
+	// completeness, but keeping secrets out of the config file is preferred.
+	Secret        string `json:"secret,omitempty"`
+	SecretFile    string `json:"secret_file,omitempty"`
+	SecretCommand string `json:"secret_command,omitempty"`
+	SecretEnv     string `json:"secret_env,omitempty"`
+
+	BaseURL string `json:"base_url,omitempty"`
+	// Output is the default output format.
+	Output string `json:"output,omitempty"`
+	// Production profiles require confirmation before running commands that
+	// change data.
+	Production bool `json:"production,omitempty"`
+}
+
+// configPath returns the path of the config file.
+func (a *app) configPath() (string, error) {
+	if a.configFile != "" {
+		return a.configFile, nil
+	}
+	if path := a.getenv(envConfig); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("finding config dir: %w", err)
+	}
+	return filepath.Join(dir, "convertkit", "config.json"), nil
+}
+
+// loadConfig reads the config file. A missing file results in an empty
+// config.
+func (a *app) loadConfig() (*config, string, error) {
+	path, err := a.configPath()
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &config{}, path, nil
+	}
+	if err != nil {
+		return nil, path, fmt.Errorf("reading config: %w", err)
+	}
+	var cfg config
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		return nil, path, fmt.Errorf("decoding config %s: %w", path, err)
+	}
+	return &cfg, path, nil
+}
+
+// selectProfile picks the profile to use. A profile named with -profile or
+// CONVERTKIT_PROFILE is always used. Otherwise CONVERTKIT_SECRET takes
+// precedence over the config file's default profile, so the name returned is
+// empty if the secret should come from the environment.
+func (a *app) selectProfile() (string, *profile, error) {
+	name := a.profileName
+	if name == "" {
+		name = a.getenv(envProfile)
+	}
+	if name == "" && a.getenv(envSecret) != "" {
+		return "", nil, nil
+	}
+	cfg, path, err := a.loadConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	if name == "" {
+		return "", nil, nil
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok || p == nil {
+		return "", nil, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	return name, p, nil
+}
+
+// secret returns the profile's secret.
+func (p *profile) secret(getenv func(string) string) (string, error) {
+	var secret string
+	switch {
+	case p.SecretCommand != "":
+		var stdout, stderr bytes.Buffer
+		cmd := shellCommand(p.SecretCommand)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err != nil {
+			return "", fmt.Errorf("running secret_command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		secret = stdout.String()
+	case p.SecretFile != "":
+		b, err := ioutil.ReadFile(expandHome(p.SecretFile))
+		if err != nil {
+			return "", fmt.Errorf("reading secret_file: %w", err)
+		}
+		secret = string(b)
+	case p.SecretEnv != "":
+		secret = getenv(p.SecretEnv)
+		if secret == "" {
+			return "", fmt.Errorf("secret_env %s is not set", p.SecretEnv)
+		}
+	default:
+		secret = p.Secret
+	}
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("profile has no secret")
+	}
+	return secret, nil
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// confirm asks the user to type the profile name before a command that
+// changes data is run against a production profile.
+func (a *app) confirm(name string) error {
+	banner := strings.Repeat("!", 72)
+	fmt.Fprintf(a.stderr, "%s\n!!! PRODUCTION: %q will change data using the production profile %q.\n%s\n", banner, a.cmd.name, name, banner)
+	fmt.Fprintf(a.stderr, "Type the profile name to continue: ")
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(a.stderr)
+		return fmt.Errorf("aborted: no confirmation")
+	}
+	if strings.TrimSpace(line) != name {
+		return fmt.Errorf("aborted: confirmation did not match %q", name)
+	}
+	return nil
+}
+
+func profilesListCmd(a *app, args []string) error {
+	err := a.noArgs("profiles list", args)
+	if err != nil {
+		return err
+	}
+	cfg, _, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type profileInfo struct {
+		Name       string `json:"name"`
+		Default    bool   `json:"default"`
+		Production bool   `json:"production"`
+		BaseURL    string `json:"base_url,omitempty"`
+		SecretFrom string `json:"secret_from"`
+	}
+	infos := []profileInfo{}
+	t := table{header: []string{"name", "default", "production", "base_url", "secret_from"}}
+	for _, name := range names {
+		p := cfg.Profiles[name]
+		info := profileInfo{
+			Name:       name,
+			Default:    name == cfg.DefaultProfile,
+			Production: p.Production,
+			BaseURL:    p.BaseURL,
+			SecretFrom: p.secretSource(),
+		}
+		infos = append(infos, info)
+		t.add(info.Name, fmt.Sprint(info.Default), fmt.Sprint(info.Production), info.BaseURL, info.SecretFrom)
+	}
+	return a.print(infos, t)
+}
+
+// secretSource describes where the secret comes from without revealing it.
+func (p *profile) secretSource() string {
+	switch {
+	case p.SecretCommand != "":
+		return "command"
+	case p.SecretFile != "":
+		return "file " + p.SecretFile
+	case p.SecretEnv != "":
+		return "env " + p.SecretEnv
+	case p.Secret != "":
+		return "config"
+	}
+	return "none"
+}
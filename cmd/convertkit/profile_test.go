@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+// writeConfig writes a config with a staging and a production profile that
+// both use srv, and returns its path.
+func writeConfig(t *testing.T, srv *convertkittest.Server) string {
+	t.Helper()
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "staging.key")
+	err := ioutil.WriteFile(secretFile, []byte(srv.Secret+"\n"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	cfg := config{
+		DefaultProfile: "staging",
+		Profiles: map[string]*profile{
+			"staging": {
+				SecretFile: secretFile,
+				BaseURL:    srv.URL,
+			},
+			"production": {
+				SecretCommand: "echo " + srv.Secret,
+				BaseURL:       srv.URL,
+				Output:        "json",
+				Production:    true,
+			},
+			"broken": {
+				SecretEnv: "UNSET_SECRET",
+			},
+		},
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() err = %v; want nil", err)
+	}
+	path := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(path, b, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	return path
+}
+
+func TestRun_profiles(t *testing.T) {
+	srv := server(t)
+	path := writeConfig(t, srv)
+	tests := map[string]struct {
+		env    map[string]string
+		stdin  string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		"default profile": {
+			args:   []string{"account"},
+			stdout: "Acme Corp.",
+		},
+		"profile flag": {
+			args:   []string{"--profile", "production", "account"},
+			stdout: `"name": "Acme Corp."`,
+		},
+		"profile env": {
+			env:    map[string]string{envProfile: "production"},
+			args:   []string{"account"},
+			stdout: `"name": "Acme Corp."`,
+		},
+		"profile beats secret env": {
+			env:    map[string]string{envSecret: "wrong", envProfile: "staging"},
+			args:   []string{"account"},
+			stdout: "Acme Corp.",
+		},
+		"secret env beats default profile": {
+			env:    map[string]string{envSecret: "wrong", envBaseURL: srv.URL},
+			args:   []string{"account"},
+			code:   exitError,
+			stderr: "401",
+		},
+		"unknown profile": {
+			args:   []string{"-profile", "dev", "account"},
+			code:   exitError,
+			stderr: `profile "dev" not found`,
+		},
+		"missing secret": {
+			args:   []string{"-profile", "broken", "account"},
+			code:   exitError,
+			stderr: `profile "broken": secret_env UNSET_SECRET is not set`,
+		},
+		"production confirmed": {
+			stdin:  "production\n",
+			args:   []string{"-profile", "production", "tags", "create", "Lead"},
+			stdout: `"name": "Lead"`,
+			stderr: "!!! PRODUCTION",
+		},
+		"production not confirmed": {
+			stdin:  "yes\n",
+			args:   []string{"-profile", "production", "tags", "create", "Lead"},
+			code:   exitError,
+			stderr: `aborted: confirmation did not match "production"`,
+		},
+		"production without stdin": {
+			args:   []string{"-profile", "production", "untag", "1", "14"},
+			code:   exitError,
+			stderr: "aborted: no confirmation",
+		},
+		"production with yes": {
+			args:   []string{"-profile", "production", "-yes", "tags", "create", "Lead"},
+			stdout: `"name": "Lead"`,
+		},
+		"production read only": {
+			args:   []string{"-profile", "production", "tags", "list"},
+			stdout: `"name": "Customer"`,
+		},
+		"production usage error": {
+			args:   []string{"-profile", "production", "untag", "1"},
+			code:   exitUsage,
+			stderr: "a subscriber ID and a tag ID are required",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := map[string]string{envConfig: path}
+			for k, v := range tc.env {
+				env[k] = v
+			}
+			code, stdout, stderr := runWith(env, tc.stdin, tc.args...)
+			if code != tc.code {
+				t.Errorf("exit code = %d; want %d\nstderr: %s", code, tc.code, stderr)
+			}
+			if !strings.Contains(stdout, tc.stdout) {
+				t.Errorf("stdout = %q; want it to contain %q", stdout, tc.stdout)
+			}
+			if !strings.Contains(stderr, tc.stderr) {
+				t.Errorf("stderr = %q; want it to contain %q", stderr, tc.stderr)
+			}
+		})
+	}
+}
+
+func TestRun_profilesList(t *testing.T) {
+	srv := convertkittest.NewServer("sk-live-abc123")
+	defer srv.Close()
+	path := writeConfig(t, srv)
+	code, stdout, stderr := runWith(map[string]string{envConfig: path}, "", "-o", "csv", "profiles", "list")
+	if code != exitOK {
+		t.Fatalf("exit code = %d; want %d\nstderr: %s", code, exitOK, stderr)
+	}
+	if strings.Contains(stdout, srv.Secret) {
+		t.Errorf("stdout = %q; want the secret to be hidden", stdout)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	want := []string{
+		"name,default,production,base_url,secret_from",
+		"broken,false,false,,env UNSET_SECRET",
+		"production,false,true," + srv.URL + ",command",
+	}
+	for i, w := range want {
+		if i >= len(lines) || lines[i] != w {
+			t.Errorf("line %d = %q; want %q", i, lines[i], w)
+		}
+	}
+}
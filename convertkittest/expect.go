@@ -0,0 +1,243 @@
+package convertkittest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Expectation describes a request a test expects to be made. Expectations are
+// usually loaded from METHOD_path.request.json fixtures, eg:
+//
+//	{
+//	  "query": {"page": "2"},
+//	  "body": {"email": "jonsnow@example.com", "tags": [14]},
+//	  "partial": true,
+//	  "ignore": ["body.fields"]
+//	}
+//
+// The api_secret parameter is never compared since it is checked elsewhere.
+type Expectation struct {
+	// Name is the fixture name of the request, eg "POST_forms_213_subscribe".
+	// See FixtureName.
+	Name string `json:"-"`
+	// Query, if set, is compared with the request's query params.
+	Query map[string]string `json:"query,omitempty"`
+	// Body, if set, is compared with the request's JSON body.
+	Body json.RawMessage `json:"body,omitempty"`
+	// Partial allows the request to have query params and object keys that
+	// aren't in the expectation.
+	Partial bool `json:"partial,omitempty"`
+	// Ignore lists paths that aren't compared, eg "query.page" or
+	// "body.fields.last_name".
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// LoadExpectation reads the expectation for name from dir/name.request.json.
+func LoadExpectation(dir, name string) (Expectation, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, name+".request.json"))
+	if err != nil {
+		return Expectation{}, fmt.Errorf("convertkittest: reading expectation: %w", err)
+	}
+	var e Expectation
+	err = json.Unmarshal(b, &e)
+	if err != nil {
+		return Expectation{}, fmt.Errorf("convertkittest: decoding %s.request.json: %w", name, err)
+	}
+	e.Name = name
+	return e, nil
+}
+
+// Diff compares a request and its body with the expectation and returns a
+// line describing each difference. A nil result means the request matches.
+func (e Expectation) Diff(r *http.Request, body []byte) []string {
+	d := differ{partial: e.Partial, ignore: make(map[string]bool)}
+	for _, path := range e.Ignore {
+		d.ignore[path] = true
+	}
+	d.ignore["query.api_secret"] = true
+	d.ignore["body.api_secret"] = true
+
+	if name := FixtureName(r, "/"); name != e.Name {
+		d.add("request", "want %s, got %s", e.Name, name)
+	}
+	if e.Query != nil {
+		want := make(map[string]interface{}, len(e.Query))
+		for k, v := range e.Query {
+			want[k] = v
+		}
+		got := make(map[string]interface{})
+		for k, v := range r.URL.Query() {
+			got[k] = v[0]
+		}
+		d.compare("query", want, got)
+	}
+	if e.Body != nil {
+		var want, got interface{}
+		err := json.Unmarshal(e.Body, &want)
+		if err != nil {
+			d.add("body", "invalid expectation: %v", err)
+			return d.diffs
+		}
+		err = json.Unmarshal(body, &got)
+		if err != nil {
+			d.add("body", "want JSON, got %q (%v)", body, err)
+			return d.diffs
+		}
+		d.compare("body", want, got)
+	}
+	return d.diffs
+}
+
+type differ struct {
+	partial bool
+	ignore  map[string]bool
+	diffs   []string
+}
+
+func (d *differ) add(path, format string, args ...interface{}) {
+	d.diffs = append(d.diffs, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (d *differ) compare(path string, want, got interface{}) {
+	if d.ignore[path] {
+		return
+	}
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			d.add(path, "want %s, got %s", jsonString(want), jsonString(got))
+			return
+		}
+		for _, k := range sortedKeys(w) {
+			gv, ok := g[k]
+			if !ok {
+				if !d.ignore[path+"."+k] {
+					d.add(path+"."+k, "missing, want %s", jsonString(w[k]))
+				}
+				continue
+			}
+			d.compare(path+"."+k, w[k], gv)
+		}
+		if d.partial {
+			return
+		}
+		for _, k := range sortedKeys(g) {
+			if _, ok := w[k]; !ok && !d.ignore[path+"."+k] {
+				d.add(path+"."+k, "unexpected, got %s", jsonString(g[k]))
+			}
+		}
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			d.add(path, "want %s, got %s", jsonString(want), jsonString(got))
+			return
+		}
+		for i := range w {
+			d.compare(fmt.Sprintf("%s[%d]", path, i), w[i], g[i])
+		}
+	default:
+		if jsonString(want) != jsonString(got) {
+			d.add(path, "want %s, got %s", jsonString(want), jsonString(got))
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonString(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// Expectations checks the requests received by a test server against a list
+// of expected requests. Each expectation matches one request. Requests that
+// weren't expected, requests that don't match their expectation, and
+// expectations that are never met when the test ends are all reported as
+// test errors.
+type Expectations struct {
+	tb      testing.TB
+	mu      sync.Mutex
+	pending []Expectation
+}
+
+// Expect loads the expectations for each name from dir. The same name can be
+// listed more than once to expect multiple calls.
+func Expect(tb testing.TB, dir string, names ...string) *Expectations {
+	tb.Helper()
+	e := &Expectations{tb: tb}
+	for _, name := range names {
+		exp, err := LoadExpectation(dir, name)
+		if err != nil {
+			tb.Fatalf("%v", err)
+		}
+		e.Add(exp)
+	}
+	tb.Cleanup(e.verify)
+	return e
+}
+
+// Add adds an expectation.
+func (e *Expectations) Add(exp Expectation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pending = append(e.pending, exp)
+}
+
+// Handler checks each request against the expectations before passing it to
+// next.
+func (e *Expectations) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			e.tb.Errorf("reading request body: %v", err)
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		e.check(r, body)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (e *Expectations) check(r *http.Request, body []byte) {
+	name := FixtureName(r, "/")
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, exp := range e.pending {
+		if exp.Name != name {
+			continue
+		}
+		e.pending = append(e.pending[:i], e.pending[i+1:]...)
+		if diffs := exp.Diff(r, body); len(diffs) > 0 {
+			e.tb.Errorf("request %s does not match %s.request.json:\n\t%s", name, name, strings.Join(diffs, "\n\t"))
+		}
+		return
+	}
+	e.tb.Errorf("unexpected request %s %s\n\tbody: %s", r.Method, r.URL.Path, body)
+}
+
+func (e *Expectations) verify() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, exp := range e.pending {
+		e.tb.Errorf("missing request %s (%s.request.json)", exp.Name, exp.Name)
+	}
+}
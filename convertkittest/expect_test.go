@@ -0,0 +1,145 @@
+package convertkittest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func TestExpectation_Diff(t *testing.T) {
+	tests := map[string]struct {
+		name   string
+		exp    string
+		method string
+		url    string
+		body   string
+		want   []string
+	}{
+		"match": {
+			exp:    `{"body": {"email": "jon@example.com", "tags": [14]}}`,
+			method: http.MethodPost,
+			url:    "/forms/213/subscribe",
+			body:   `{"api_secret": "x", "email": "jon@example.com", "tags": [14]}`,
+		},
+		"wrong type": {
+			exp:    `{"body": {"email": "jon@example.com", "tags": [14]}}`,
+			method: http.MethodPost,
+			url:    "/forms/213/subscribe",
+			body:   `{"email": "jon@example.com", "tags": "14"}`,
+			want:   []string{`body.tags: want [14], got "14"`},
+		},
+		"unexpected and missing": {
+			exp:    `{"body": {"first_name": "Bob", "fields": {"a": "b"}}}`,
+			method: http.MethodPost,
+			url:    "/forms/213/subscribe",
+			body:   `{"first_name": "Bob", "email_address": ""}`,
+			want: []string{
+				`body.fields: missing, want {"a":"b"}`,
+				`body.email_address: unexpected, got ""`,
+			},
+		},
+		"partial": {
+			exp:    `{"body": {"first_name": "Bob"}, "partial": true}`,
+			method: http.MethodPost,
+			url:    "/forms/213/subscribe",
+			body:   `{"first_name": "Bob", "email_address": ""}`,
+		},
+		"ignore": {
+			exp:    `{"body": {"fields": {"a": "b"}}, "ignore": ["body.fields.a", "body.email"]}`,
+			method: http.MethodPost,
+			url:    "/forms/213/subscribe",
+			body:   `{"fields": {"a": "c"}, "email": "jon@example.com"}`,
+		},
+		"query": {
+			name:   "GET_forms_213_subscriptions",
+			exp:    `{"query": {"sort_order": "asc"}}`,
+			method: http.MethodGet,
+			url:    "/forms/213/subscriptions?api_secret=x&sort_order=desc&subscriber_state=active",
+			want: []string{
+				`query.sort_order: want "asc", got "desc"`,
+				`query.subscriber_state: unexpected, got "active"`,
+			},
+		},
+		"wrong request": {
+			exp:    `{}`,
+			method: http.MethodPut,
+			url:    "/forms/213/subscribe",
+			want:   []string{"request: want POST_forms_213_subscribe, got PUT_forms_213_subscribe"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var exp convertkittest.Expectation
+			err := json.Unmarshal([]byte(tc.exp), &exp)
+			if err != nil {
+				t.Fatalf("Unmarshal() err = %v; want nil", err)
+			}
+			exp.Name = tc.name
+			if exp.Name == "" {
+				exp.Name = "POST_forms_213_subscribe"
+			}
+			r := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			got := exp.Diff(r, []byte(tc.body))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Diff() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeTB captures errors so that tests can check what Expectations reports.
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func TestExpectations(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	exp := convertkittest.Expect(tb, "../testdata", "POST_forms_213_subscribe", "PUT_subscribers_123")
+	server := httptest.NewServer(exp.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})))
+	defer server.Close()
+	c := &convertkit.Client{Secret: "secret", BaseURL: server.URL}
+
+	_, err := c.SubscribeToForm(convertkit.SubscribeToFormRequest{
+		FormID: 213,
+		Email:  "jonsnow@example.com",
+		TagIDs: convertkit.TagIDs(14),
+	})
+	if err != nil {
+		t.Fatalf("SubscribeToForm() err = %v; want nil", err)
+	}
+	c.Tags()
+	for _, f := range tb.cleanups {
+		f()
+	}
+
+	want := []string{"unexpected request GET /tags", "missing request PUT_subscribers_123"}
+	if len(tb.errors) != len(want) {
+		t.Fatalf("errors = %q; want %d errors", tb.errors, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(tb.errors[i], want[i]) {
+			t.Errorf("errors[%d] = %q; want prefix %q", i, tb.errors[i], want[i])
+		}
+	}
+}
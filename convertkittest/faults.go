@@ -0,0 +1,324 @@
+package convertkittest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FaultKind is a type of misbehaviour a FaultInjector can simulate.
+type FaultKind int
+
+// Supported faults.
+const (
+	// FaultNone passes the request through untouched.
+	FaultNone FaultKind = iota
+	// FaultLatency delays the request by Fault.Latency before passing it
+	// through.
+	FaultLatency
+	// FaultTimeout hangs until FaultInjector.Timeout passes and then returns
+	// a net.Error whose Timeout method reports true.
+	FaultTimeout
+	// FaultReset fails the request with a connection reset error.
+	FaultReset
+	// FaultRateLimit responds with a 429 and a Retry-After header.
+	FaultRateLimit
+	// FaultServerError responds with a 500 and a JSON error body.
+	FaultServerError
+	// FaultTruncated passes the request through, but the response body ends
+	// halfway through with io.ErrUnexpectedEOF.
+	FaultTruncated
+	// FaultHTML responds with an HTML error page like the ones served by load
+	// balancers, instead of JSON.
+	FaultHTML
+	// FaultWrongShape passes the request through, but a JSON object in the
+	// response body is wrapped in an array and an array is replaced with its
+	// first element.
+	FaultWrongShape
+)
+
+var faultKindNames = map[FaultKind]string{
+	FaultNone:        "none",
+	FaultLatency:     "latency",
+	FaultTimeout:     "timeout",
+	FaultReset:       "reset",
+	FaultRateLimit:   "rate_limit",
+	FaultServerError: "server_error",
+	FaultTruncated:   "truncated",
+	FaultHTML:        "html",
+	FaultWrongShape:  "wrong_shape",
+}
+
+func (k FaultKind) String() string {
+	if name, ok := faultKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("FaultKind(%d)", int(k))
+}
+
+// Fault is a single injected fault.
+type Fault struct {
+	Kind FaultKind
+	// Latency is added before the fault is applied, regardless of its kind.
+	Latency time.Duration
+	// RetryAfter is used as the Retry-After header for FaultRateLimit.
+	// Defaults to one second.
+	RetryAfter time.Duration
+	// StatusCode overrides the status code used by FaultRateLimit,
+	// FaultServerError, and FaultHTML.
+	StatusCode int
+}
+
+func (f Fault) String() string {
+	if f.Latency > 0 && f.Kind != FaultLatency {
+		return fmt.Sprintf("%v after %v", f.Kind, f.Latency)
+	}
+	if f.Kind == FaultLatency {
+		return fmt.Sprintf("%v of %v", f.Kind, f.Latency)
+	}
+	return f.Kind.String()
+}
+
+// DefaultTimeout is how long FaultTimeout hangs when FaultInjector.Timeout
+// isn't set.
+const DefaultTimeout = 30 * time.Second
+
+// FaultInjector is an HTTPClient that wraps another HTTPClient and injects
+// faults into some of its calls.
+//
+// Faults in Script are used in order, one per call. Once the script runs out
+// (or if there isn't one) each call has a Rate chance of getting a random
+// fault from Kinds, chosen using a random source seeded with Seed. Calls made
+// in the same order will always get the same faults.
+type FaultInjector struct {
+	// HTTPClient handles requests that reach the server. Defaults to
+	// http.DefaultClient.
+	HTTPClient HTTPClient
+	// Script lists the faults to inject into each call, in order. Use
+	// FaultNone for calls that should succeed.
+	Script []Fault
+	// Seed seeds the random faults used after Script runs out.
+	Seed int64
+	// Rate is the probability, from 0 to 1, of a random fault being injected
+	// into a call.
+	Rate float64
+	// Kinds are the faults chosen from at random. Defaults to every kind
+	// except FaultNone.
+	Kinds []FaultKind
+	// Latency is used by random FaultLatency faults. Defaults to one second.
+	Latency time.Duration
+	// Timeout is how long FaultTimeout hangs. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	calls    int
+	injected []Fault
+}
+
+// Injected returns the fault used for each call so far, in order.
+func (fi *FaultInjector) Injected() []Fault {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return append([]Fault(nil), fi.injected...)
+}
+
+func (fi *FaultInjector) next() Fault {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	var f Fault
+	if fi.calls < len(fi.Script) {
+		f = fi.Script[fi.calls]
+	} else {
+		if fi.rand == nil {
+			fi.rand = rand.New(rand.NewSource(fi.Seed))
+		}
+		if fi.rand.Float64() < fi.Rate {
+			kinds := fi.Kinds
+			if len(kinds) == 0 {
+				for k := FaultLatency; k <= FaultWrongShape; k++ {
+					kinds = append(kinds, k)
+				}
+			}
+			f.Kind = kinds[fi.rand.Intn(len(kinds))]
+			if f.Kind == FaultLatency {
+				f.Latency = fi.Latency
+				if f.Latency == 0 {
+					f.Latency = time.Second
+				}
+			}
+		}
+	}
+	fi.calls++
+	fi.injected = append(fi.injected, f)
+	return f
+}
+
+// Do performs the request with the next fault applied.
+func (fi *FaultInjector) Do(r *http.Request) (*http.Response, error) {
+	f := fi.next()
+	ctx := r.Context()
+	if f.Latency > 0 {
+		err := sleep(ctx, f.Latency)
+		if err != nil {
+			return nil, urlError(r, err)
+		}
+	}
+	switch f.Kind {
+	case FaultTimeout:
+		timeout := fi.Timeout
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		err := sleep(ctx, timeout)
+		if err != nil {
+			return nil, urlError(r, err)
+		}
+		return nil, urlError(r, timeoutError{})
+	case FaultReset:
+		return nil, urlError(r, &net.OpError{
+			Op:  "read",
+			Net: "tcp",
+			Err: os.NewSyscallError("read", syscall.ECONNRESET),
+		})
+	case FaultRateLimit:
+		retryAfter := f.RetryAfter
+		if retryAfter == 0 {
+			retryAfter = time.Second
+		}
+		resp := fakeResponse(r, statusCode(f, http.StatusTooManyRequests), "application/json",
+			`{"error":"Too Many Requests","message":"Rate limit exceeded"}`)
+		resp.Header.Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		return resp, nil
+	case FaultServerError:
+		return fakeResponse(r, statusCode(f, http.StatusInternalServerError), "application/json",
+			`{"error":"Internal Server Error","message":"Something went wrong"}`), nil
+	case FaultHTML:
+		code := statusCode(f, http.StatusBadGateway)
+		return fakeResponse(r, code, "text/html; charset=utf-8", fmt.Sprintf(
+			"<html>\n<head><title>%[1]d %[2]s</title></head>\n<body>\n<center><h1>%[1]d %[2]s</h1></center>\n</body>\n</html>\n",
+			code, http.StatusText(code))), nil
+	}
+
+	httpClient := fi.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	switch f.Kind {
+	case FaultTruncated:
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(io.MultiReader(
+			bytes.NewReader(b[:len(b)/2]),
+			errReader{io.ErrUnexpectedEOF},
+		))
+		resp.ContentLength = int64(len(b))
+	case FaultWrongShape:
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		b = reshape(b)
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		resp.ContentLength = int64(len(b))
+	}
+	return resp, nil
+}
+
+// reshape wraps a JSON object in an array, and replaces an array with its
+// first element. Anything else is returned as-is.
+func reshape(b []byte) []byte {
+	var v interface{}
+	if json.Unmarshal(b, &v) != nil {
+		return b
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return append(append([]byte("["), bytes.TrimSpace(b)...), ']')
+	case []interface{}:
+		if len(v) == 0 {
+			return []byte("{}")
+		}
+		first, err := json.Marshal(v[0])
+		if err != nil {
+			return b
+		}
+		return first
+	}
+	return b
+}
+
+func statusCode(f Fault, fallback int) int {
+	if f.StatusCode != 0 {
+		return f.StatusCode
+	}
+	return fallback
+}
+
+func fakeResponse(r *http.Request, code int, contentType, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+// urlError wraps err the same way an *http.Client does.
+func urlError(r *http.Request, err error) error {
+	return &url.Error{Op: urlOp(r.Method), URL: r.URL.String(), Err: err}
+}
+
+func urlOp(method string) string {
+	if method == "" {
+		return "Get"
+	}
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "convertkittest: injected timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
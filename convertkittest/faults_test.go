@@ -0,0 +1,173 @@
+package convertkittest_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func TestFaultInjector(t *testing.T) {
+	statusIs := func(code int) func(*testing.T, error) {
+		return func(t *testing.T, err error) {
+			var errResp convertkit.ErrorResponse
+			if !errors.As(err, &errResp) || errResp.StatusCode != code {
+				t.Errorf("err = %v; want ErrorResponse with status %d", err, code)
+			}
+		}
+	}
+	tests := map[string]struct {
+		fault convertkittest.Fault
+		check func(*testing.T, error)
+	}{
+		"none": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultNone},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("err = %v; want nil", err)
+				}
+			},
+		},
+		"latency": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultLatency, Latency: time.Millisecond},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("err = %v; want nil", err)
+				}
+			},
+		},
+		"timeout": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultTimeout},
+			check: func(t *testing.T, err error) {
+				var netErr net.Error
+				if !errors.As(err, &netErr) || !netErr.Timeout() {
+					t.Errorf("err = %v; want a timeout", err)
+				}
+			},
+		},
+		"reset": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultReset},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, syscall.ECONNRESET) {
+					t.Errorf("err = %v; want %v", err, syscall.ECONNRESET)
+				}
+			},
+		},
+		"rate limit":   {fault: convertkittest.Fault{Kind: convertkittest.FaultRateLimit}, check: statusIs(429)},
+		"server error": {fault: convertkittest.Fault{Kind: convertkittest.FaultServerError}, check: statusIs(500)},
+		"html": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultHTML},
+			check: func(t *testing.T, err error) {
+				// ErrorResponse is only used for JSON errors.
+				if err == nil {
+					t.Errorf("err = nil; want a decoding error")
+				}
+			},
+		},
+		"truncated": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultTruncated},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Errorf("err = %v; want %v", err, io.ErrUnexpectedEOF)
+				}
+			},
+		},
+		"wrong shape": {
+			fault: convertkittest.Fault{Kind: convertkittest.FaultWrongShape},
+			check: func(t *testing.T, err error) {
+				if err == nil {
+					t.Errorf("err = nil; want a decoding error")
+				}
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := server(t).Client()
+			c.HTTPClient = &convertkittest.FaultInjector{
+				HTTPClient: c.HTTPClient,
+				Script:     []convertkittest.Fault{tc.fault},
+				Timeout:    time.Millisecond,
+			}
+			_, err := c.Tags()
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestFaultInjector_retryAfter(t *testing.T) {
+	fi := &convertkittest.FaultInjector{
+		Script: []convertkittest.Fault{{Kind: convertkittest.FaultRateLimit, RetryAfter: 1500 * time.Millisecond}},
+	}
+	r, _ := http.NewRequest(http.MethodGet, "http://convertkit.test/tags", nil)
+	resp, err := fi.Do(r)
+	if err != nil {
+		t.Fatalf("Do() err = %v; want nil", err)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q; want %q", got, "2")
+	}
+}
+
+func TestFaultInjector_wrongShapeCreateTags(t *testing.T) {
+	// A single object wrapped in an array is handled by CreateTagsResponse.
+	c := server(t).Client()
+	c.HTTPClient = &convertkittest.FaultInjector{
+		HTTPClient: c.HTTPClient,
+		Script:     []convertkittest.Fault{{Kind: convertkittest.FaultWrongShape}},
+	}
+	resp, err := c.CreateTags("Lead")
+	if err != nil {
+		t.Fatalf("CreateTags() err = %v; want nil", err)
+	}
+	if len(resp.Tags) != 1 || resp.Tags[0].Name != "Lead" {
+		t.Errorf("Tags = %+v; want Lead", resp.Tags)
+	}
+}
+
+func TestFaultInjector_seed(t *testing.T) {
+	kinds := func(seed int64) []convertkittest.FaultKind {
+		c := server(t).Client()
+		fi := &convertkittest.FaultInjector{
+			HTTPClient: c.HTTPClient,
+			Script:     []convertkittest.Fault{{Kind: convertkittest.FaultServerError}},
+			Seed:       seed,
+			Rate:       0.5,
+			Kinds:      []convertkittest.FaultKind{convertkittest.FaultRateLimit, convertkittest.FaultServerError},
+		}
+		c.HTTPClient = fi
+		for i := 0; i < 20; i++ {
+			c.Account()
+		}
+		var ret []convertkittest.FaultKind
+		for _, f := range fi.Injected() {
+			ret = append(ret, f.Kind)
+		}
+		return ret
+	}
+	a, b := kinds(42), kinds(42)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("faults differ with the same seed:\n%v\n%v", a, b)
+	}
+	if a[0] != convertkittest.FaultServerError {
+		t.Errorf("first fault = %v; want the scripted %v", a[0], convertkittest.FaultServerError)
+	}
+	var none, faults int
+	for _, k := range a[1:] {
+		if k == convertkittest.FaultNone {
+			none++
+		} else {
+			faults++
+		}
+	}
+	if none == 0 || faults == 0 {
+		t.Errorf("faults = %v; want a mix of faults and successes", a)
+	}
+}
@@ -0,0 +1,239 @@
+package convertkittest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// HTTPClient matches the HTTPClient field of convertkit.Client.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// ErrNoFixture is returned by a Replayer when a request doesn't have a
+// matching fixture.
+var ErrNoFixture = errors.New("convertkittest: no fixture for request")
+
+// DefaultDir is the directory fixtures are read from and written to when none
+// is provided.
+const DefaultDir = "testdata"
+
+// FixtureName returns the name of the fixture used for a request, using the
+// same naming scheme as the tests in the convertkit package. The name is the
+// HTTP method followed by the path relative to baseURL with slashes replaced
+// by underscores, eg "POST_tags_14_subscribe". Pages after the first have
+// "_page_N" appended. If baseURL is empty convertkit.DefaultBaseURL is used.
+func FixtureName(r *http.Request, baseURL string) string {
+	if baseURL == "" {
+		baseURL = convertkit.DefaultBaseURL
+	}
+	basePath := "/"
+	if u, err := url.Parse(baseURL); err == nil && u.Path != "" {
+		basePath = u.Path
+	}
+	path := strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(basePath, "/"))
+	name := fmt.Sprintf("%s_%s", r.Method, strings.ReplaceAll(strings.Trim(path, "/"), "/", "_"))
+	if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page > 1 {
+		name = fmt.Sprintf("%s_page_%d", name, page)
+	}
+	return name
+}
+
+// Recorder is an HTTPClient that performs real requests and saves every
+// response as a fixture. Each fixture is made up of a METHOD_path.json file
+// with the response body and a METHOD_path.headers.json file with the status
+// code.
+//
+// The secret and all email addresses are redacted from recorded bodies. Each
+// email address is replaced with a fake one, and the same address always gets
+// the same replacement so relationships between fixtures are preserved.
+type Recorder struct {
+	// HTTPClient performs the real requests. Defaults to http.DefaultClient.
+	HTTPClient HTTPClient
+	// BaseURL is the BaseURL of the convertkit.Client being recorded. Defaults
+	// to convertkit.DefaultBaseURL.
+	BaseURL string
+	// Dir is where fixtures are written. Defaults to DefaultDir.
+	Dir string
+	// Secret is redacted from every fixture.
+	Secret string
+
+	mu     sync.Mutex
+	emails map[string]string
+}
+
+var emailRegexp = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Do performs the request and records the response.
+func (rec *Recorder) Do(r *http.Request) (*http.Response, error) {
+	httpClient := rec.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("convertkittest: reading response: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	err = rec.save(FixtureName(r, rec.BaseURL), resp.StatusCode, b)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (rec *Recorder) save(name string, statusCode int, body []byte) error {
+	dir := rec.Dir
+	if dir == "" {
+		dir = DefaultDir
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("convertkittest: creating fixture dir: %w", err)
+	}
+	body = rec.redact(body)
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, body, "", "  ") == nil {
+		body = append(pretty.Bytes(), '\n')
+	}
+	headers, err := json.MarshalIndent(struct {
+		StatusCode int `json:"status_code"`
+	}{statusCode}, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name+".json"), body, 0644)
+	if err != nil {
+		return fmt.Errorf("convertkittest: writing fixture: %w", err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name+".headers.json"), append(headers, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("convertkittest: writing fixture: %w", err)
+	}
+	return nil
+}
+
+// redact replaces the secret and email addresses in b.
+func (rec *Recorder) redact(b []byte) []byte {
+	if rec.Secret != "" {
+		b = bytes.ReplaceAll(b, []byte(rec.Secret), []byte("REDACTED"))
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.emails == nil {
+		rec.emails = make(map[string]string)
+	}
+	return emailRegexp.ReplaceAllFunc(b, func(email []byte) []byte {
+		key := strings.ToLower(string(email))
+		fake, ok := rec.emails[key]
+		if !ok {
+			fake = fmt.Sprintf("subscriber%d@example.com", len(rec.emails)+1)
+			rec.emails[key] = fake
+		}
+		return []byte(fake)
+	})
+}
+
+// Replayer is an HTTPClient that serves responses from fixtures written by a
+// Recorder (or by hand) instead of making real requests. Requests without a
+// matching fixture fail with ErrNoFixture, and if TB is set the test is
+// marked as failed too.
+type Replayer struct {
+	// BaseURL is the BaseURL of the convertkit.Client being replayed. Defaults
+	// to convertkit.DefaultBaseURL.
+	BaseURL string
+	// Dir is where fixtures are read from. Defaults to DefaultDir.
+	Dir string
+	// TB, if set, is used to report missing fixtures.
+	TB testing.TB
+}
+
+// Do returns the recorded response for the request.
+func (rp *Replayer) Do(r *http.Request) (*http.Response, error) {
+	dir := rp.Dir
+	if dir == "" {
+		dir = DefaultDir
+	}
+	name := FixtureName(r, rp.BaseURL)
+	path := filepath.Join(dir, name+".json")
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		err = fmt.Errorf("%w: %s %s (expected %s)", ErrNoFixture, r.Method, r.URL.Path, path)
+		if rp.TB != nil {
+			rp.TB.Helper()
+			rp.TB.Errorf("%v", err)
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("convertkittest: reading fixture: %w", err)
+	}
+	statusCode := http.StatusOK
+	headers, err := ioutil.ReadFile(filepath.Join(dir, name+".headers.json"))
+	if err == nil {
+		var h struct {
+			StatusCode int `json:"status_code"`
+		}
+		err = json.Unmarshal(headers, &h)
+		if err != nil {
+			return nil, fmt.Errorf("convertkittest: decoding %s.headers.json: %w", name, err)
+		}
+		statusCode = h.StatusCode
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("convertkittest: reading fixture: %w", err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}, nil
+}
+
+// Environment variables used by FixtureClient.
+const (
+	EnvRecord = "CONVERTKIT_RECORD"
+	EnvSecret = "CONVERTKIT_SECRET"
+)
+
+// FixtureClient returns a Recorder using the secret in CONVERTKIT_SECRET if
+// CONVERTKIT_RECORD is set, and a Replayer otherwise. This makes it possible
+// to refresh fixtures by re-running tests:
+//
+//	CONVERTKIT_RECORD=1 CONVERTKIT_SECRET=... go test ./...
+//
+// The secret returned should be used as the client's Secret.
+func FixtureClient(tb testing.TB, dir string) (HTTPClient, string) {
+	if os.Getenv(EnvRecord) == "" {
+		return &Replayer{Dir: dir, TB: tb}, "secret"
+	}
+	secret := os.Getenv(EnvSecret)
+	if secret == "" {
+		tb.Fatalf("%s must be set when %s is set", EnvSecret, EnvRecord)
+	}
+	return &Recorder{Dir: dir, Secret: secret}, secret
+}
@@ -0,0 +1,128 @@
+package convertkittest_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func TestFixtureName(t *testing.T) {
+	tests := map[string]struct {
+		method  string
+		url     string
+		baseURL string
+		want    string
+	}{
+		"default base url": {
+			method: http.MethodPost,
+			url:    "https://api.convertkit.com/v3/tags/14/subscribe",
+			want:   "POST_tags_14_subscribe",
+		},
+		"test server": {
+			method:  http.MethodGet,
+			url:     "http://127.0.0.1:1234/forms?api_secret=x",
+			baseURL: "http://127.0.0.1:1234",
+			want:    "GET_forms",
+		},
+		"first page": {
+			method:  http.MethodGet,
+			url:     "http://127.0.0.1:1234/subscribers?page=1",
+			baseURL: "http://127.0.0.1:1234/",
+			want:    "GET_subscribers",
+		},
+		"later page": {
+			method:  http.MethodGet,
+			url:     "http://127.0.0.1:1234/subscribers?page=2",
+			baseURL: "http://127.0.0.1:1234/",
+			want:    "GET_subscribers_page_2",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.url, nil)
+			if got := convertkittest.FixtureName(r, tc.baseURL); got != tc.want {
+				t.Errorf("FixtureName() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	const secret = "super-secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"subscriber":{"id":1,"email_address":"Jon@Snow.com","first_name":"` + secret + `"},"other":"jon@snow.com"}`))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	rec := &convertkittest.Recorder{BaseURL: server.URL, Dir: dir, Secret: secret}
+	c := &convertkit.Client{Secret: secret, BaseURL: server.URL, HTTPClient: rec}
+
+	resp, err := c.UpdateSubscriber(convertkit.UpdateSubscriberRequest{SubscriberID: 1, FirstName: "Jon"})
+	if err != nil {
+		t.Fatalf("UpdateSubscriber() err = %v; want nil", err)
+	}
+	if resp.Email != "Jon@Snow.com" {
+		t.Errorf("Email = %q; want the unredacted response", resp.Email)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(dir, "PUT_subscribers_1.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	for _, leaked := range []string{secret, "snow.com", "Snow.com"} {
+		if strings.Contains(string(body), leaked) {
+			t.Errorf("fixture contains %q:\n%s", leaked, body)
+		}
+	}
+	if got := strings.Count(string(body), "subscriber1@example.com"); got != 2 {
+		t.Errorf("fixture has %d redacted emails; want 2:\n%s", got, body)
+	}
+	headers, err := ioutil.ReadFile(filepath.Join(dir, "PUT_subscribers_1.headers.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	if want := "{\n  \"status_code\": 201\n}\n"; string(headers) != want {
+		t.Errorf("headers = %q; want %q", headers, want)
+	}
+
+	c.HTTPClient = &convertkittest.Replayer{BaseURL: server.URL, Dir: dir, TB: t}
+	server.Close()
+	replayed, err := c.UpdateSubscriber(convertkit.UpdateSubscriberRequest{SubscriberID: 1, FirstName: "Jon"})
+	if err != nil {
+		t.Fatalf("UpdateSubscriber() err = %v; want nil", err)
+	}
+	if replayed.Email != "subscriber1@example.com" {
+		t.Errorf("Email = %q; want %q", replayed.Email, "subscriber1@example.com")
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	c := &convertkit.Client{
+		Secret:     "secret",
+		BaseURL:    "http://convertkit.test",
+		HTTPClient: &convertkittest.Replayer{BaseURL: "http://convertkit.test", Dir: "../testdata"},
+	}
+	t.Run("fixture", func(t *testing.T) {
+		resp, err := c.Tags()
+		if err != nil {
+			t.Fatalf("Tags() err = %v; want nil", err)
+		}
+		if len(resp.Tags) == 0 {
+			t.Errorf("len(Tags) = 0; want fixture tags")
+		}
+	})
+	t.Run("missing fixture", func(t *testing.T) {
+		err := c.Do(http.MethodGet, "broadcasts", nil, nil)
+		if !errors.Is(err, convertkittest.ErrNoFixture) {
+			t.Errorf("Do() err = %v; want %v", err, convertkittest.ErrNoFixture)
+		}
+	})
+}
@@ -0,0 +1,342 @@
+package convertkittest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// ErrNotStubbed is returned by Mock methods that don't have a stub.
+var ErrNotStubbed = errors.New("convertkittest: method not stubbed")
+
+// Call is a single call made to a Mock.
+type Call struct {
+	// Method is the name of the method called, eg "SubscribeToForm".
+	Method string
+	// Args are the arguments the method was called with. Variadic arguments
+	// are passed as a single slice.
+	Args []interface{}
+}
+
+// Mock is an implementation of convertkit.API for unit tests. Each method
+// calls the matching Func field, eg Forms calls FormsFunc, and returns
+// ErrNotStubbed if it is nil. Every call is recorded, whether or not it is
+// stubbed. Mock is safe for concurrent use as long as the Func fields aren't
+// changed while it is in use.
+type Mock struct {
+	AccountFunc                   func() (*convertkit.AccountResponse, error)
+	SubscribersFunc               func(convertkit.SubscribersRequest) (*convertkit.SubscribersResponse, error)
+	SubscriberFunc                func(convertkit.SubscriberID) (*convertkit.SubscriberResponse, error)
+	SubscriberTagsFunc            func(convertkit.SubscriberID) (*convertkit.SubscriberTagsResponse, error)
+	UpdateSubscriberFunc          func(convertkit.UpdateSubscriberRequest) (*convertkit.UpdateSubscriberResponse, error)
+	UnsubscribeSubscriberFunc     func(string) (*convertkit.UnsubscribeSubscriberResponse, error)
+	UpsertSubscriberFunc          func(convertkit.UpsertSubscriberRequest) (*convertkit.UpsertSubscriberResponse, error)
+	TagsFunc                      func() (*convertkit.TagsResponse, error)
+	CreateTagsFunc                func(...string) (*convertkit.CreateTagsResponse, error)
+	EnsureTagsFunc                func(...string) (map[string]convertkit.Tag, error)
+	TagSubscriberFunc             func(convertkit.TagSubscriberRequest) (*convertkit.TagSubscriberResponse, error)
+	TagSubscriberByNameFunc       func(string, convertkit.TagSubscriberRequest) (*convertkit.TagSubscriberResponse, error)
+	UntagSubscriberFunc           func(convertkit.UntagSubscriberRequest) (*convertkit.UntagSubscriberResponse, error)
+	UntagSubscriberByNameFunc     func(string, convertkit.UntagSubscriberRequest) (*convertkit.UntagSubscriberResponse, error)
+	TagSubscriptionsFunc          func(convertkit.TagSubscriptionsRequest) (*convertkit.TagSubscriptionsResponse, error)
+	BulkTagFunc                   func(context.Context, convertkit.TagID, []convertkit.BulkTarget, convertkit.BulkOptions) (*convertkit.BulkSummary, error)
+	BulkUntagFunc                 func(context.Context, convertkit.TagID, []convertkit.BulkTarget, convertkit.BulkOptions) (*convertkit.BulkSummary, error)
+	FormsFunc                     func() (*convertkit.FormsResponse, error)
+	SubscribeToFormFunc           func(convertkit.SubscribeToFormRequest) (*convertkit.SubscribeToFormResponse, error)
+	SubscribeToFormByNameFunc     func(string, convertkit.SubscribeToFormRequest) (*convertkit.SubscribeToFormResponse, error)
+	FormSubscriptionsFunc         func(convertkit.FormSubscriptionsRequest) (*convertkit.FormSubscriptionsResponse, error)
+	SequencesFunc                 func() (*convertkit.SequencesResponse, error)
+	SubscribeToSequenceFunc       func(convertkit.SubscribeToSequenceRequest) (*convertkit.SubscribeToSequenceResponse, error)
+	SubscribeToSequenceByNameFunc func(string, convertkit.SubscribeToSequenceRequest) (*convertkit.SubscribeToSequenceResponse, error)
+	SequenceSubscriptionsFunc     func(convertkit.SequenceSubscriptionsRequest) (*convertkit.SequenceSubscriptionsResponse, error)
+	CreateWebhookFunc             func(convertkit.CreateWebhookRequest) (*convertkit.CreateWebhookResponse, error)
+	DeleteWebhookFunc             func(convertkit.WebhookRuleID) (*convertkit.DeleteWebhookResponse, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ convertkit.API = (*Mock)(nil)
+
+// Calls returns every call made to the mock, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to a single method, in order.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ret []Call
+	for _, c := range m.calls {
+		if c.Method == method {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Reset forgets every recorded call. Stubs are left as-is.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
+
+// Account records the call and calls AccountFunc.
+func (m *Mock) Account() (*convertkit.AccountResponse, error) {
+	m.record("Account")
+	if m.AccountFunc == nil {
+		return nil, notStubbed("Account")
+	}
+	return m.AccountFunc()
+}
+
+// Subscribers records the call and calls SubscribersFunc.
+func (m *Mock) Subscribers(req convertkit.SubscribersRequest) (*convertkit.SubscribersResponse, error) {
+	m.record("Subscribers", req)
+	if m.SubscribersFunc == nil {
+		return nil, notStubbed("Subscribers")
+	}
+	return m.SubscribersFunc(req)
+}
+
+// Subscriber records the call and calls SubscriberFunc.
+func (m *Mock) Subscriber(id convertkit.SubscriberID) (*convertkit.SubscriberResponse, error) {
+	m.record("Subscriber", id)
+	if m.SubscriberFunc == nil {
+		return nil, notStubbed("Subscriber")
+	}
+	return m.SubscriberFunc(id)
+}
+
+// SubscriberTags records the call and calls SubscriberTagsFunc.
+func (m *Mock) SubscriberTags(id convertkit.SubscriberID) (*convertkit.SubscriberTagsResponse, error) {
+	m.record("SubscriberTags", id)
+	if m.SubscriberTagsFunc == nil {
+		return nil, notStubbed("SubscriberTags")
+	}
+	return m.SubscriberTagsFunc(id)
+}
+
+// UpdateSubscriber records the call and calls UpdateSubscriberFunc.
+func (m *Mock) UpdateSubscriber(req convertkit.UpdateSubscriberRequest) (*convertkit.UpdateSubscriberResponse, error) {
+	m.record("UpdateSubscriber", req)
+	if m.UpdateSubscriberFunc == nil {
+		return nil, notStubbed("UpdateSubscriber")
+	}
+	return m.UpdateSubscriberFunc(req)
+}
+
+// UnsubscribeSubscriber records the call and calls UnsubscribeSubscriberFunc.
+func (m *Mock) UnsubscribeSubscriber(email string) (*convertkit.UnsubscribeSubscriberResponse, error) {
+	m.record("UnsubscribeSubscriber", email)
+	if m.UnsubscribeSubscriberFunc == nil {
+		return nil, notStubbed("UnsubscribeSubscriber")
+	}
+	return m.UnsubscribeSubscriberFunc(email)
+}
+
+// UpsertSubscriber records the call and calls UpsertSubscriberFunc.
+func (m *Mock) UpsertSubscriber(req convertkit.UpsertSubscriberRequest) (*convertkit.UpsertSubscriberResponse, error) {
+	m.record("UpsertSubscriber", req)
+	if m.UpsertSubscriberFunc == nil {
+		return nil, notStubbed("UpsertSubscriber")
+	}
+	return m.UpsertSubscriberFunc(req)
+}
+
+// Tags records the call and calls TagsFunc.
+func (m *Mock) Tags() (*convertkit.TagsResponse, error) {
+	m.record("Tags")
+	if m.TagsFunc == nil {
+		return nil, notStubbed("Tags")
+	}
+	return m.TagsFunc()
+}
+
+// CreateTags records the call and calls CreateTagsFunc.
+func (m *Mock) CreateTags(tags ...string) (*convertkit.CreateTagsResponse, error) {
+	m.record("CreateTags", tags)
+	if m.CreateTagsFunc == nil {
+		return nil, notStubbed("CreateTags")
+	}
+	return m.CreateTagsFunc(tags...)
+}
+
+// EnsureTags records the call and calls EnsureTagsFunc.
+func (m *Mock) EnsureTags(names ...string) (map[string]convertkit.Tag, error) {
+	m.record("EnsureTags", names)
+	if m.EnsureTagsFunc == nil {
+		return nil, notStubbed("EnsureTags")
+	}
+	return m.EnsureTagsFunc(names...)
+}
+
+// TagSubscriber records the call and calls TagSubscriberFunc.
+func (m *Mock) TagSubscriber(req convertkit.TagSubscriberRequest) (*convertkit.TagSubscriberResponse, error) {
+	m.record("TagSubscriber", req)
+	if m.TagSubscriberFunc == nil {
+		return nil, notStubbed("TagSubscriber")
+	}
+	return m.TagSubscriberFunc(req)
+}
+
+// TagSubscriberByName records the call and calls TagSubscriberByNameFunc.
+func (m *Mock) TagSubscriberByName(tag string, req convertkit.TagSubscriberRequest) (*convertkit.TagSubscriberResponse, error) {
+	m.record("TagSubscriberByName", tag, req)
+	if m.TagSubscriberByNameFunc == nil {
+		return nil, notStubbed("TagSubscriberByName")
+	}
+	return m.TagSubscriberByNameFunc(tag, req)
+}
+
+// UntagSubscriber records the call and calls UntagSubscriberFunc.
+func (m *Mock) UntagSubscriber(req convertkit.UntagSubscriberRequest) (*convertkit.UntagSubscriberResponse, error) {
+	m.record("UntagSubscriber", req)
+	if m.UntagSubscriberFunc == nil {
+		return nil, notStubbed("UntagSubscriber")
+	}
+	return m.UntagSubscriberFunc(req)
+}
+
+// UntagSubscriberByName records the call and calls UntagSubscriberByNameFunc.
+func (m *Mock) UntagSubscriberByName(tag string, req convertkit.UntagSubscriberRequest) (*convertkit.UntagSubscriberResponse, error) {
+	m.record("UntagSubscriberByName", tag, req)
+	if m.UntagSubscriberByNameFunc == nil {
+		return nil, notStubbed("UntagSubscriberByName")
+	}
+	return m.UntagSubscriberByNameFunc(tag, req)
+}
+
+// TagSubscriptions records the call and calls TagSubscriptionsFunc.
+func (m *Mock) TagSubscriptions(req convertkit.TagSubscriptionsRequest) (*convertkit.TagSubscriptionsResponse, error) {
+	m.record("TagSubscriptions", req)
+	if m.TagSubscriptionsFunc == nil {
+		return nil, notStubbed("TagSubscriptions")
+	}
+	return m.TagSubscriptionsFunc(req)
+}
+
+// BulkTag records the call and calls BulkTagFunc.
+func (m *Mock) BulkTag(ctx context.Context, tag convertkit.TagID, targets []convertkit.BulkTarget, opts convertkit.BulkOptions) (*convertkit.BulkSummary, error) {
+	m.record("BulkTag", ctx, tag, targets, opts)
+	if m.BulkTagFunc == nil {
+		return nil, notStubbed("BulkTag")
+	}
+	return m.BulkTagFunc(ctx, tag, targets, opts)
+}
+
+// BulkUntag records the call and calls BulkUntagFunc.
+func (m *Mock) BulkUntag(ctx context.Context, tag convertkit.TagID, targets []convertkit.BulkTarget, opts convertkit.BulkOptions) (*convertkit.BulkSummary, error) {
+	m.record("BulkUntag", ctx, tag, targets, opts)
+	if m.BulkUntagFunc == nil {
+		return nil, notStubbed("BulkUntag")
+	}
+	return m.BulkUntagFunc(ctx, tag, targets, opts)
+}
+
+// Forms records the call and calls FormsFunc.
+func (m *Mock) Forms() (*convertkit.FormsResponse, error) {
+	m.record("Forms")
+	if m.FormsFunc == nil {
+		return nil, notStubbed("Forms")
+	}
+	return m.FormsFunc()
+}
+
+// SubscribeToForm records the call and calls SubscribeToFormFunc.
+func (m *Mock) SubscribeToForm(req convertkit.SubscribeToFormRequest) (*convertkit.SubscribeToFormResponse, error) {
+	m.record("SubscribeToForm", req)
+	if m.SubscribeToFormFunc == nil {
+		return nil, notStubbed("SubscribeToForm")
+	}
+	return m.SubscribeToFormFunc(req)
+}
+
+// SubscribeToFormByName records the call and calls SubscribeToFormByNameFunc.
+func (m *Mock) SubscribeToFormByName(form string, req convertkit.SubscribeToFormRequest) (*convertkit.SubscribeToFormResponse, error) {
+	m.record("SubscribeToFormByName", form, req)
+	if m.SubscribeToFormByNameFunc == nil {
+		return nil, notStubbed("SubscribeToFormByName")
+	}
+	return m.SubscribeToFormByNameFunc(form, req)
+}
+
+// FormSubscriptions records the call and calls FormSubscriptionsFunc.
+func (m *Mock) FormSubscriptions(req convertkit.FormSubscriptionsRequest) (*convertkit.FormSubscriptionsResponse, error) {
+	m.record("FormSubscriptions", req)
+	if m.FormSubscriptionsFunc == nil {
+		return nil, notStubbed("FormSubscriptions")
+	}
+	return m.FormSubscriptionsFunc(req)
+}
+
+// Sequences records the call and calls SequencesFunc.
+func (m *Mock) Sequences() (*convertkit.SequencesResponse, error) {
+	m.record("Sequences")
+	if m.SequencesFunc == nil {
+		return nil, notStubbed("Sequences")
+	}
+	return m.SequencesFunc()
+}
+
+// SubscribeToSequence records the call and calls SubscribeToSequenceFunc.
+func (m *Mock) SubscribeToSequence(req convertkit.SubscribeToSequenceRequest) (*convertkit.SubscribeToSequenceResponse, error) {
+	m.record("SubscribeToSequence", req)
+	if m.SubscribeToSequenceFunc == nil {
+		return nil, notStubbed("SubscribeToSequence")
+	}
+	return m.SubscribeToSequenceFunc(req)
+}
+
+// SubscribeToSequenceByName records the call and calls SubscribeToSequenceByNameFunc.
+func (m *Mock) SubscribeToSequenceByName(sequence string, req convertkit.SubscribeToSequenceRequest) (*convertkit.SubscribeToSequenceResponse, error) {
+	m.record("SubscribeToSequenceByName", sequence, req)
+	if m.SubscribeToSequenceByNameFunc == nil {
+		return nil, notStubbed("SubscribeToSequenceByName")
+	}
+	return m.SubscribeToSequenceByNameFunc(sequence, req)
+}
+
+// SequenceSubscriptions records the call and calls SequenceSubscriptionsFunc.
+func (m *Mock) SequenceSubscriptions(req convertkit.SequenceSubscriptionsRequest) (*convertkit.SequenceSubscriptionsResponse, error) {
+	m.record("SequenceSubscriptions", req)
+	if m.SequenceSubscriptionsFunc == nil {
+		return nil, notStubbed("SequenceSubscriptions")
+	}
+	return m.SequenceSubscriptionsFunc(req)
+}
+
+// CreateWebhook records the call and calls CreateWebhookFunc.
+func (m *Mock) CreateWebhook(req convertkit.CreateWebhookRequest) (*convertkit.CreateWebhookResponse, error) {
+	m.record("CreateWebhook", req)
+	if m.CreateWebhookFunc == nil {
+		return nil, notStubbed("CreateWebhook")
+	}
+	return m.CreateWebhookFunc(req)
+}
+
+// DeleteWebhook records the call and calls DeleteWebhookFunc.
+func (m *Mock) DeleteWebhook(ruleID convertkit.WebhookRuleID) (*convertkit.DeleteWebhookResponse, error) {
+	m.record("DeleteWebhook", ruleID)
+	if m.DeleteWebhookFunc == nil {
+		return nil, notStubbed("DeleteWebhook")
+	}
+	return m.DeleteWebhookFunc(ruleID)
+}
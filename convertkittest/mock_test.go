@@ -0,0 +1,65 @@
+package convertkittest_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func TestMock(t *testing.T) {
+	var m convertkittest.Mock
+	m.SubscribeToFormFunc = func(req convertkit.SubscribeToFormRequest) (*convertkit.SubscribeToFormResponse, error) {
+		var resp convertkit.SubscribeToFormResponse
+		resp.Subscription.Subscriber.Email = req.Email
+		return &resp, nil
+	}
+	var api convertkit.FormAPI = &m
+	resp, err := api.SubscribeToForm(convertkit.SubscribeToFormRequest{FormID: 213, Email: "jon@example.com"})
+	if err != nil {
+		t.Fatalf("SubscribeToForm() err = %v; want nil", err)
+	}
+	if resp.Subscription.Subscriber.Email != "jon@example.com" {
+		t.Errorf("Email = %q; want %q", resp.Subscription.Subscriber.Email, "jon@example.com")
+	}
+
+	_, err = m.CreateTags("a", "b")
+	if !errors.Is(err, convertkittest.ErrNotStubbed) {
+		t.Errorf("CreateTags() err = %v; want %v", err, convertkittest.ErrNotStubbed)
+	}
+
+	want := []convertkittest.Call{
+		{Method: "SubscribeToForm", Args: []interface{}{convertkit.SubscribeToFormRequest{FormID: 213, Email: "jon@example.com"}}},
+		{Method: "CreateTags", Args: []interface{}{[]string{"a", "b"}}},
+	}
+	if got := m.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %+v; want %+v", got, want)
+	}
+	if got := m.CallsTo("CreateTags"); !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("CallsTo() = %+v; want %+v", got, want[1:])
+	}
+	m.Reset()
+	if got := m.Calls(); len(got) != 0 {
+		t.Errorf("Calls() after Reset = %+v; want none", got)
+	}
+}
+
+// TestMock_stubs makes sure the mock has a stub for every method in
+// convertkit.API, with a matching signature.
+func TestMock_stubs(t *testing.T) {
+	api := reflect.TypeOf((*convertkit.API)(nil)).Elem()
+	mock := reflect.TypeOf(convertkittest.Mock{})
+	for i := 0; i < api.NumMethod(); i++ {
+		method := api.Method(i)
+		field, ok := mock.FieldByName(method.Name + "Func")
+		if !ok {
+			t.Errorf("Mock has no %sFunc field", method.Name)
+			continue
+		}
+		if field.Type != method.Type {
+			t.Errorf("%sFunc type = %v; want %v", method.Name, field.Type, method.Type)
+		}
+	}
+}
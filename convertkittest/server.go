@@ -0,0 +1,767 @@
+// Package convertkittest provides a stateful, in-memory fake of the Convert
+// Kit API for use in tests.
+//
+// Unlike static fixtures, the fake keeps track of everything that happens, so
+// a CreateTags call followed by a Tags call will include the new tag:
+//
+//	srv := convertkittest.NewServer("secret")
+//	defer srv.Close()
+//	srv.Seed(convertkittest.Fixtures{
+//		Forms: []convertkit.Form{{Name: "Newsletter"}},
+//	})
+//	client := srv.Client()
+//	// use client like you would a real *convertkit.Client
+package convertkittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// PageSize is the number of results returned per page by paginated endpoints.
+const PageSize = 50
+
+// Fixtures are used to seed a Server with data.
+type Fixtures struct {
+	Account     *convertkit.AccountResponse
+	Forms       []convertkit.Form
+	Sequences   []convertkit.Sequence
+	Tags        []convertkit.Tag
+	Subscribers []convertkit.Subscriber
+}
+
+// Server is a fake Convert Kit API backed by in-memory state. It supports
+// every endpoint implemented by the convertkit package, and rejects requests
+// that don't use its secret. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server.
+	URL string
+	// Secret is the API secret requests must use.
+	Secret string
+	// Now returns the time used for created_at values. Defaults to time.Now.
+	Now func() time.Time
+
+	server *httptest.Server
+
+	mu            sync.Mutex
+	nextID        int
+	account       convertkit.AccountResponse
+	forms         []convertkit.Form
+	sequences     []convertkit.Sequence
+	tags          []convertkit.Tag
+	subscribers   []convertkit.Subscriber
+	subscriptions []subscription
+	webhooks      []convertkit.WebhookRule
+}
+
+type subscription struct {
+	ID               int
+	State            string
+	CreatedAt        time.Time
+	SubscribableID   int
+	SubscribableType string
+	SubscriberID     convertkit.SubscriberID
+}
+
+// Types of subscriptions. Sequences were previously called courses, and the
+// API still uses that name.
+const (
+	typeForm     = "form"
+	typeSequence = "course"
+	typeTag      = "tag"
+)
+
+// NewServer starts a Server that requires secret. Call Close when done.
+func NewServer(secret string) *Server {
+	s := &Server{
+		Secret: secret,
+		nextID: 1,
+		account: convertkit.AccountResponse{
+			Name:         "Acme Corp.",
+			PrimaryEmail: "you@example.com",
+		},
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a *convertkit.Client configured to use the server.
+func (s *Server) Client() *convertkit.Client {
+	return &convertkit.Client{
+		Secret:     s.Secret,
+		BaseURL:    s.URL,
+		HTTPClient: s.server.Client(),
+	}
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (s *Server) id() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// Seed adds the fixtures to the server. Any resources with a zero ID are
+// assigned one, and zero CreatedAt values are set to the current time.
+func (s *Server) Seed(f Fixtures) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.Account != nil {
+		s.account = *f.Account
+	}
+	for _, form := range f.Forms {
+		s.addForm(form)
+	}
+	for _, seq := range f.Sequences {
+		s.addSequence(seq)
+	}
+	for _, tag := range f.Tags {
+		s.addTag(tag.Name, tag)
+	}
+	for _, sub := range f.Subscribers {
+		s.addSubscriber(sub)
+	}
+}
+
+func (s *Server) reserve(id int) {
+	if id >= s.nextID {
+		s.nextID = id + 1
+	}
+}
+
+func (s *Server) addForm(form convertkit.Form) convertkit.Form {
+	if form.ID == 0 {
+		form.ID = convertkit.FormID(s.id())
+	}
+	s.reserve(int(form.ID))
+	if form.CreatedAt.IsZero() {
+		form.CreatedAt = s.now()
+	}
+	s.forms = append(s.forms, form)
+	return form
+}
+
+func (s *Server) addSequence(seq convertkit.Sequence) convertkit.Sequence {
+	if seq.ID == 0 {
+		seq.ID = convertkit.SequenceID(s.id())
+	}
+	s.reserve(int(seq.ID))
+	if seq.CreatedAt.IsZero() {
+		seq.CreatedAt = s.now()
+	}
+	s.sequences = append(s.sequences, seq)
+	return seq
+}
+
+func (s *Server) addTag(name string, tag convertkit.Tag) convertkit.Tag {
+	tag.Name = name
+	if tag.ID == 0 {
+		tag.ID = convertkit.TagID(s.id())
+	}
+	s.reserve(int(tag.ID))
+	if tag.CreatedAt.IsZero() {
+		tag.CreatedAt = s.now()
+	}
+	s.tags = append(s.tags, tag)
+	return tag
+}
+
+func (s *Server) addSubscriber(sub convertkit.Subscriber) convertkit.Subscriber {
+	if sub.ID == 0 {
+		sub.ID = convertkit.SubscriberID(s.id())
+	}
+	s.reserve(int(sub.ID))
+	if sub.State == "" {
+		sub.State = "active"
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = s.now()
+	}
+	// Copy the fields so that later updates don't change the caller's map.
+	sub = copySubscriber(sub)
+	s.subscribers = append(s.subscribers, sub)
+	return sub
+}
+
+// Forms returns every form on the server.
+func (s *Server) Forms() []convertkit.Form {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]convertkit.Form(nil), s.forms...)
+}
+
+// Sequences returns every sequence on the server.
+func (s *Server) Sequences() []convertkit.Sequence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]convertkit.Sequence(nil), s.sequences...)
+}
+
+// Tags returns every tag on the server.
+func (s *Server) Tags() []convertkit.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]convertkit.Tag(nil), s.tags...)
+}
+
+// Subscribers returns every subscriber on the server.
+func (s *Server) Subscribers() []convertkit.Subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]convertkit.Subscriber, len(s.subscribers))
+	for i, sub := range s.subscribers {
+		ret[i] = copySubscriber(sub)
+	}
+	return ret
+}
+
+// SubscriberTags returns the IDs of the tags applied to a subscriber.
+func (s *Server) SubscriberTags(id convertkit.SubscriberID) []convertkit.TagID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ret []convertkit.TagID
+	for _, sn := range s.subscriptions {
+		if sn.SubscriberID == id && sn.SubscribableType == typeTag {
+			ret = append(ret, convertkit.TagID(sn.SubscribableID))
+		}
+	}
+	return ret
+}
+
+// Webhooks returns every webhook registered with the server.
+func (s *Server) Webhooks() []convertkit.WebhookRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]convertkit.WebhookRule(nil), s.webhooks...)
+}
+
+func copySubscriber(sub convertkit.Subscriber) convertkit.Subscriber {
+	fields := make(map[string]string, len(sub.Fields))
+	for k, v := range sub.Fields {
+		fields[k] = v
+	}
+	sub.Fields = fields
+	return sub
+}
+
+// apiError is written when a request fails.
+type apiError struct {
+	status  int
+	Type    string `json:"error"`
+	Message string `json:"message"`
+}
+
+func notFound(format string, args ...interface{}) *apiError {
+	return &apiError{
+		status:  http.StatusNotFound,
+		Type:    "Not Found",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func badRequest(format string, args ...interface{}) *apiError {
+	return &apiError{
+		status:  http.StatusUnprocessableEntity,
+		Type:    "Unprocessable Entity",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// params holds the decoded parameters of a request, regardless of whether
+// they were sent as query params or a JSON body.
+type params map[string]interface{}
+
+func (p params) str(key string) string {
+	switch v := p[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func (p params) int(key string) int {
+	n, _ := strconv.Atoi(p.str(key))
+	return n
+}
+
+func (p params) fields() map[string]string {
+	m, ok := p["fields"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		ret[k] = fmt.Sprintf("%v", v)
+	}
+	return ret
+}
+
+func (p params) tagIDs() []convertkit.TagID {
+	var ret []convertkit.TagID
+	switch v := p["tags"].(type) {
+	case []interface{}:
+		for _, id := range v {
+			if f, ok := id.(float64); ok {
+				ret = append(ret, convertkit.TagID(f))
+			}
+		}
+	}
+	return ret
+}
+
+func readParams(r *http.Request) (params, error) {
+	p := make(params)
+	switch r.Method {
+	case http.MethodGet, http.MethodDelete:
+		for k, v := range r.URL.Query() {
+			p[k] = v[0]
+		}
+		return p, nil
+	}
+	err := json.NewDecoder(r.Body).Decode(&p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	p, err := readParams(r)
+	if err != nil {
+		writeError(w, &apiError{status: http.StatusBadRequest, Type: "Bad Request", Message: err.Error()})
+		return
+	}
+	if p.str("api_secret") != s.Secret {
+		writeError(w, &apiError{
+			status:  http.StatusUnauthorized,
+			Type:    "Authorization Failed",
+			Message: "API Key not valid",
+		})
+		return
+	}
+	// Responses share maps and slices with the server's state, so they are
+	// encoded before the lock is released.
+	s.mu.Lock()
+	resp, apiErr := s.route(r.Method, strings.Split(strings.Trim(r.URL.Path, "/"), "/"), p)
+	var body []byte
+	if apiErr == nil {
+		body, err = json.Marshal(resp)
+	}
+	s.mu.Unlock()
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, e *apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(e)
+}
+
+// route dispatches a request to the matching endpoint. s.mu must be held.
+func (s *Server) route(method string, path []string, p params) (interface{}, *apiError) {
+	id := func(i int) (int, bool) {
+		if len(path) <= i {
+			return 0, false
+		}
+		n, err := strconv.Atoi(path[i])
+		return n, err == nil
+	}
+	route := method + " " + path[0]
+	switch {
+	case route == "GET account" && len(path) == 1:
+		return s.account, nil
+	case route == "GET forms" && len(path) == 1:
+		return convertkit.FormsResponse{Forms: s.forms}, nil
+	case route == "GET sequences" && len(path) == 1:
+		return convertkit.SequencesResponse{Sequences: s.sequences}, nil
+	case route == "GET tags" && len(path) == 1:
+		return convertkit.TagsResponse{Tags: s.tags}, nil
+	case route == "POST tags" && len(path) == 1:
+		return s.createTags(p)
+	case route == "GET subscribers" && len(path) == 1:
+		return s.listSubscribers(p)
+	case route == "PUT unsubscribe" && len(path) == 1:
+		return s.unsubscribe(p)
+	case route == "POST automations" && len(path) == 2 && path[1] == "hooks":
+		return s.createWebhook(p)
+	case route == "DELETE automations" && len(path) == 3 && path[1] == "hooks":
+		if n, ok := id(2); ok {
+			return s.deleteWebhook(convertkit.WebhookRuleID(n))
+		}
+	case len(path) == 3 && (path[0] == "forms" || path[0] == "sequences" || path[0] == "tags"):
+		n, ok := id(1)
+		if !ok {
+			break
+		}
+		typ := map[string]string{"forms": typeForm, "sequences": typeSequence, "tags": typeTag}[path[0]]
+		switch method + " " + path[2] {
+		case "POST subscribe":
+			return s.subscribe(typ, n, p)
+		case "GET subscriptions":
+			return s.listSubscriptions(typ, n, p)
+		}
+	case route == "GET subscribers" && len(path) == 2:
+		if n, ok := id(1); ok {
+			i := s.findSubscriber(convertkit.SubscriberID(n))
+			if i < 0 {
+				return nil, notFound("subscriber %d not found", n)
+			}
+			return convertkit.SubscriberResponse{Subscriber: s.subscribers[i]}, nil
+		}
+	case route == "GET subscribers" && len(path) == 3 && path[2] == "tags":
+		if n, ok := id(1); ok {
+			return s.subscriberTags(convertkit.SubscriberID(n))
+		}
+	case route == "PUT subscribers" && len(path) == 2:
+		if n, ok := id(1); ok {
+			return s.updateSubscriber(convertkit.SubscriberID(n), p)
+		}
+	case route == "DELETE subscribers" && len(path) == 4 && path[2] == "tags":
+		subID, ok1 := id(1)
+		tagID, ok2 := id(3)
+		if ok1 && ok2 {
+			return s.untag(convertkit.SubscriberID(subID), convertkit.TagID(tagID))
+		}
+	}
+	return nil, notFound("%s /%s is not supported", method, strings.Join(path, "/"))
+}
+
+func (s *Server) findSubscriberByEmail(email string) int {
+	for i, sub := range s.subscribers {
+		if strings.EqualFold(sub.Email, email) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) findSubscriber(id convertkit.SubscriberID) int {
+	for i, sub := range s.subscribers {
+		if sub.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) findTag(id convertkit.TagID) int {
+	for i, tag := range s.tags {
+		if tag.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) subscribableExists(typ string, id int) bool {
+	switch typ {
+	case typeForm:
+		for _, f := range s.forms {
+			if int(f.ID) == id {
+				return true
+			}
+		}
+	case typeSequence:
+		for _, seq := range s.sequences {
+			if int(seq.ID) == id {
+				return true
+			}
+		}
+	case typeTag:
+		return s.findTag(convertkit.TagID(id)) >= 0
+	}
+	return false
+}
+
+func (s *Server) createTags(p params) (interface{}, *apiError) {
+	raw, ok := p["tag"].([]interface{})
+	if !ok {
+		if single, ok := p["tag"].(map[string]interface{}); ok {
+			raw = []interface{}{single}
+		}
+	}
+	var created []convertkit.Tag
+	for _, t := range raw {
+		m, _ := t.(map[string]interface{})
+		name, _ := m["name"].(string)
+		if name == "" {
+			return nil, badRequest("tag name can't be blank")
+		}
+		created = append(created, s.addTag(name, convertkit.Tag{}))
+	}
+	if len(created) == 1 {
+		return created[0], nil
+	}
+	return created, nil
+}
+
+func (s *Server) subscribe(typ string, id int, p params) (interface{}, *apiError) {
+	if !s.subscribableExists(typ, id) {
+		return nil, notFound("%s %d not found", typ, id)
+	}
+	email := p.str("email")
+	if email == "" {
+		return nil, badRequest("email is required")
+	}
+	i := s.findSubscriberByEmail(email)
+	if i < 0 {
+		s.addSubscriber(convertkit.Subscriber{Email: email})
+		i = len(s.subscribers) - 1
+	}
+	sub := &s.subscribers[i]
+	if name := p.str("first_name"); name != "" {
+		sub.FirstName = name
+	}
+	for k, v := range p.fields() {
+		sub.Fields[k] = v
+	}
+	sn := s.addSubscription(typ, id, sub.ID)
+	for _, tagID := range p.tagIDs() {
+		if s.findTag(tagID) >= 0 {
+			s.addSubscription(typeTag, int(tagID), sub.ID)
+		}
+	}
+	return map[string]interface{}{
+		"subscription": s.subscriptionJSON(sn),
+	}, nil
+}
+
+// addSubscription adds a subscription, or returns the existing one.
+func (s *Server) addSubscription(typ string, id int, subID convertkit.SubscriberID) subscription {
+	for _, sn := range s.subscriptions {
+		if sn.SubscribableType == typ && sn.SubscribableID == id && sn.SubscriberID == subID {
+			return sn
+		}
+	}
+	sn := subscription{
+		ID:               s.id(),
+		State:            "active",
+		CreatedAt:        s.now(),
+		SubscribableID:   id,
+		SubscribableType: typ,
+		SubscriberID:     subID,
+	}
+	s.subscriptions = append(s.subscriptions, sn)
+	return sn
+}
+
+func (s *Server) subscriptionJSON(sn subscription) convertkit.Subscription {
+	ret := convertkit.Subscription{
+		ID:               sn.ID,
+		State:            sn.State,
+		CreatedAt:        sn.CreatedAt,
+		SubscribableID:   sn.SubscribableID,
+		SubscribableType: sn.SubscribableType,
+	}
+	if i := s.findSubscriber(sn.SubscriberID); i >= 0 {
+		ret.Subscriber = s.subscribers[i]
+	}
+	return ret
+}
+
+func (s *Server) listSubscriptions(typ string, id int, p params) (interface{}, *apiError) {
+	if !s.subscribableExists(typ, id) {
+		return nil, notFound("%s %d not found", typ, id)
+	}
+	state := p.str("subscriber_state")
+	var all []convertkit.Subscription
+	for _, sn := range s.subscriptions {
+		if sn.SubscribableType != typ || sn.SubscribableID != id {
+			continue
+		}
+		js := s.subscriptionJSON(sn)
+		if state != "" && js.Subscriber.State != state {
+			continue
+		}
+		all = append(all, js)
+	}
+	if p.str("sort_order") == string(convertkit.SortNewToOld) {
+		sort.SliceStable(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+	}
+	page, totalPages, start, end := paginate(len(all), p.int("page"))
+	return convertkit.FormSubscriptionsResponse{
+		TotalSubscriptions: len(all),
+		Page:               page,
+		TotalPages:         totalPages,
+		Subscriptions:      append([]convertkit.Subscription{}, all[start:end]...),
+	}, nil
+}
+
+func (s *Server) listSubscribers(p params) (interface{}, *apiError) {
+	email := p.str("email_address")
+	from, to := parseDate(p.str("from")), parseDate(p.str("to"))
+	var all []convertkit.Subscriber
+	for _, sub := range s.subscribers {
+		if email != "" && !strings.EqualFold(sub.Email, email) {
+			continue
+		}
+		if !from.IsZero() && sub.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !sub.CreatedAt.Before(to.AddDate(0, 0, 1)) {
+			continue
+		}
+		all = append(all, sub)
+	}
+	if p.str("sort_order") == string(convertkit.SortNewToOld) {
+		sort.SliceStable(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+	}
+	page, totalPages, start, end := paginate(len(all), p.int("page"))
+	return convertkit.SubscribersResponse{
+		TotalSubscribers: len(all),
+		Page:             page,
+		TotalPages:       totalPages,
+		Subscribers:      append([]convertkit.Subscriber{}, all[start:end]...),
+	}, nil
+}
+
+func parseDate(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+// paginate returns the page info and slice bounds for a list of n items.
+func paginate(n, page int) (curPage, totalPages, start, end int) {
+	if page < 1 {
+		page = 1
+	}
+	totalPages = (n + PageSize - 1) / PageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	start = (page - 1) * PageSize
+	if start > n {
+		start = n
+	}
+	end = start + PageSize
+	if end > n {
+		end = n
+	}
+	return page, totalPages, start, end
+}
+
+func (s *Server) updateSubscriber(id convertkit.SubscriberID, p params) (interface{}, *apiError) {
+	i := s.findSubscriber(id)
+	if i < 0 {
+		return nil, notFound("subscriber %d not found", id)
+	}
+	sub := &s.subscribers[i]
+	if name := p.str("first_name"); name != "" {
+		sub.FirstName = name
+	}
+	if email := p.str("email_address"); email != "" {
+		if j := s.findSubscriberByEmail(email); j >= 0 && j != i {
+			return nil, badRequest("email address %s is already taken", email)
+		}
+		sub.Email = email
+	}
+	for k, v := range p.fields() {
+		sub.Fields[k] = v
+	}
+	return convertkit.UpdateSubscriberResponse{Subscriber: *sub}, nil
+}
+
+func (s *Server) unsubscribe(p params) (interface{}, *apiError) {
+	i := s.findSubscriberByEmail(p.str("email"))
+	if i < 0 {
+		return nil, notFound("subscriber %s not found", p.str("email"))
+	}
+	s.subscribers[i].State = "cancelled"
+	return convertkit.UnsubscribeSubscriberResponse{Subscriber: s.subscribers[i]}, nil
+}
+
+func (s *Server) subscriberTags(id convertkit.SubscriberID) (interface{}, *apiError) {
+	if s.findSubscriber(id) < 0 {
+		return nil, notFound("subscriber %d not found", id)
+	}
+	tags := []convertkit.Tag{}
+	for _, sn := range s.subscriptions {
+		if sn.SubscriberID != id || sn.SubscribableType != typeTag {
+			continue
+		}
+		if i := s.findTag(convertkit.TagID(sn.SubscribableID)); i >= 0 {
+			tags = append(tags, s.tags[i])
+		}
+	}
+	return convertkit.SubscriberTagsResponse{Tags: tags}, nil
+}
+
+func (s *Server) untag(subID convertkit.SubscriberID, tagID convertkit.TagID) (interface{}, *apiError) {
+	t := s.findTag(tagID)
+	if t < 0 {
+		return nil, notFound("tag %d not found", tagID)
+	}
+	if s.findSubscriber(subID) < 0 {
+		return nil, notFound("subscriber %d not found", subID)
+	}
+	for i, sn := range s.subscriptions {
+		if sn.SubscribableType == typeTag && sn.SubscribableID == int(tagID) && sn.SubscriberID == subID {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			break
+		}
+	}
+	return s.tags[t], nil
+}
+
+func (s *Server) createWebhook(p params) (interface{}, *apiError) {
+	b, _ := json.Marshal(p["event"])
+	var event convertkit.WebhookEvent
+	json.Unmarshal(b, &event)
+	req := convertkit.CreateWebhookRequest{
+		TargetURL: p.str("target_url"),
+		Event:     event,
+	}
+	if err := req.Validate(); err != nil {
+		return nil, badRequest("%v", err)
+	}
+	rule := convertkit.WebhookRule{
+		ID:        convertkit.WebhookRuleID(s.id()),
+		AccountID: 1,
+		Event:     event,
+		TargetURL: req.TargetURL,
+	}
+	s.webhooks = append(s.webhooks, rule)
+	return convertkit.CreateWebhookResponse{Rule: rule}, nil
+}
+
+func (s *Server) deleteWebhook(id convertkit.WebhookRuleID) (interface{}, *apiError) {
+	for i, rule := range s.webhooks {
+		if rule.ID == id {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			return convertkit.DeleteWebhookResponse{Success: true}, nil
+		}
+	}
+	return nil, notFound("webhook %d not found", id)
+}
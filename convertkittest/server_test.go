@@ -0,0 +1,245 @@
+package convertkittest_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func server(t *testing.T) *convertkittest.Server {
+	t.Helper()
+	srv := convertkittest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	srv.Seed(convertkittest.Fixtures{
+		Forms:     []convertkit.Form{{ID: 213, Name: "Newsletter"}},
+		Sequences: []convertkit.Sequence{{ID: 300, Name: "Onboarding"}},
+		Tags:      []convertkit.Tag{{ID: 400, Name: "Customer"}},
+		Subscribers: []convertkit.Subscriber{
+			{ID: 500, Email: "jon@example.com", FirstName: "Jon"},
+		},
+	})
+	return srv
+}
+
+func TestServer_secret(t *testing.T) {
+	srv := server(t)
+	c := srv.Client()
+	c.Secret = "wrong"
+	_, err := c.Account()
+	var errResp convertkit.ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Account() err = %v; want ErrorResponse", err)
+	}
+	if errResp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d; want %d", errResp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestServer_CreateTags(t *testing.T) {
+	c := server(t).Client()
+	created, err := c.CreateTags("Lead", "Prospect")
+	if err != nil {
+		t.Fatalf("CreateTags() err = %v; want nil", err)
+	}
+	if len(created.Tags) != 2 {
+		t.Fatalf("len(Tags) = %d; want 2", len(created.Tags))
+	}
+	tags, err := c.Tags()
+	if err != nil {
+		t.Fatalf("Tags() err = %v; want nil", err)
+	}
+	var names []string
+	for _, tag := range tags.Tags {
+		names = append(names, tag.Name)
+	}
+	want := []string{"Customer", "Lead", "Prospect"}
+	if len(names) != len(want) {
+		t.Fatalf("Tags() = %v; want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("Tags() = %v; want %v", names, want)
+		}
+	}
+	if tags.Tags[1].ID != created.Tags[0].ID {
+		t.Errorf("Tags()[1].ID = %d; want %d", tags.Tags[1].ID, created.Tags[0].ID)
+	}
+}
+
+func TestServer_subscriptions(t *testing.T) {
+	srv := server(t)
+	c := srv.Client()
+
+	sub, err := c.SubscribeToForm(convertkit.SubscribeToFormRequest{
+		FormID:    213,
+		Email:     "new@example.com",
+		FirstName: "New",
+		Fields:    map[string]string{"plan": "pro"},
+		TagIDs:    convertkit.TagIDs(400),
+	})
+	if err != nil {
+		t.Fatalf("SubscribeToForm() err = %v; want nil", err)
+	}
+	if sub.Subscription.Subscriber.Email != "new@example.com" {
+		t.Errorf("Subscriber.Email = %q; want %q", sub.Subscription.Subscriber.Email, "new@example.com")
+	}
+	subID := sub.Subscription.Subscriber.ID
+
+	formSubs, err := c.FormSubscriptions(convertkit.FormSubscriptionsRequest{FormID: 213})
+	if err != nil {
+		t.Fatalf("FormSubscriptions() err = %v; want nil", err)
+	}
+	if formSubs.TotalSubscriptions != 1 || formSubs.Subscriptions[0].Subscriber.ID != subID {
+		t.Errorf("FormSubscriptions() = %+v; want subscriber %d", formSubs.Subscriptions, subID)
+	}
+
+	subTags, err := c.SubscriberTags(subID)
+	if err != nil {
+		t.Fatalf("SubscriberTags() err = %v; want nil", err)
+	}
+	if len(subTags.Tags) != 1 || subTags.Tags[0].ID != 400 {
+		t.Errorf("SubscriberTags() = %+v; want tag 400", subTags.Tags)
+	}
+
+	tagSubs, err := c.TagSubscriptions(convertkit.TagSubscriptionsRequest{TagID: 400})
+	if err != nil {
+		t.Fatalf("TagSubscriptions() err = %v; want nil", err)
+	}
+	if tagSubs.TotalSubscriptions != 1 {
+		t.Errorf("TagSubscriptions().TotalSubscriptions = %d; want 1", tagSubs.TotalSubscriptions)
+	}
+
+	_, err = c.SubscribeToSequence(convertkit.SubscribeToSequenceRequest{SequenceID: 300, Email: "jon@example.com"})
+	if err != nil {
+		t.Fatalf("SubscribeToSequence() err = %v; want nil", err)
+	}
+	seqSubs, err := c.SequenceSubscriptions(convertkit.SequenceSubscriptionsRequest{SequenceID: 300})
+	if err != nil {
+		t.Fatalf("SequenceSubscriptions() err = %v; want nil", err)
+	}
+	if seqSubs.TotalSubscriptions != 1 || seqSubs.Subscriptions[0].Subscriber.ID != 500 {
+		t.Errorf("SequenceSubscriptions() = %+v; want subscriber 500", seqSubs.Subscriptions)
+	}
+
+	_, err = c.UntagSubscriber(convertkit.UntagSubscriberRequest{SubscriberID: subID, TagID: 400})
+	if err != nil {
+		t.Fatalf("UntagSubscriber() err = %v; want nil", err)
+	}
+	if tags := srv.SubscriberTags(subID); len(tags) != 0 {
+		t.Errorf("SubscriberTags() = %v; want none", tags)
+	}
+
+	_, err = c.SubscribeToForm(convertkit.SubscribeToFormRequest{FormID: 999, Email: "new@example.com"})
+	var errResp convertkit.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.StatusCode != http.StatusNotFound {
+		t.Errorf("SubscribeToForm(unknown form) err = %v; want 404", err)
+	}
+}
+
+func TestServer_subscribers(t *testing.T) {
+	srv := server(t)
+	c := srv.Client()
+
+	updated, err := c.UpdateSubscriber(convertkit.UpdateSubscriberRequest{
+		SubscriberID: 500,
+		FirstName:    "Jonathan",
+		Fields:       map[string]string{"plan": "pro"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSubscriber() err = %v; want nil", err)
+	}
+	if updated.FirstName != "Jonathan" || updated.Fields["plan"] != "pro" {
+		t.Errorf("UpdateSubscriber() = %+v; want updated name and fields", updated.Subscriber)
+	}
+
+	got, err := c.Subscriber(500)
+	if err != nil {
+		t.Fatalf("Subscriber() err = %v; want nil", err)
+	}
+	if got.FirstName != "Jonathan" {
+		t.Errorf("Subscriber().FirstName = %q; want %q", got.FirstName, "Jonathan")
+	}
+
+	found, err := c.Subscribers(convertkit.SubscribersRequest{Email: "JON@example.com"})
+	if err != nil {
+		t.Fatalf("Subscribers() err = %v; want nil", err)
+	}
+	if found.TotalSubscribers != 1 || found.Subscribers[0].FirstName != "Jonathan" {
+		t.Errorf("Subscribers() = %+v; want Jonathan", found.Subscribers)
+	}
+
+	unsub, err := c.UnsubscribeSubscriber("jon@example.com")
+	if err != nil {
+		t.Fatalf("UnsubscribeSubscriber() err = %v; want nil", err)
+	}
+	if unsub.State != "cancelled" {
+		t.Errorf("State = %q; want %q", unsub.State, "cancelled")
+	}
+
+	_, err = c.UpdateSubscriber(convertkit.UpdateSubscriberRequest{SubscriberID: 999, FirstName: "x"})
+	var errResp convertkit.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.StatusCode != http.StatusNotFound {
+		t.Errorf("UpdateSubscriber(unknown) err = %v; want 404", err)
+	}
+}
+
+func TestServer_Seed_copiesFields(t *testing.T) {
+	srv := convertkittest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	fields := map[string]string{"plan": "free"}
+	srv.Seed(convertkittest.Fixtures{
+		Subscribers: []convertkit.Subscriber{{ID: 500, Email: "jon@example.com", Fields: fields}},
+	})
+	_, err := srv.Client().UpdateSubscriber(convertkit.UpdateSubscriberRequest{
+		SubscriberID: 500,
+		Fields:       map[string]string{"plan": "pro"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSubscriber() err = %v; want nil", err)
+	}
+	if fields["plan"] != "free" {
+		t.Errorf("fixture plan = %q; want %q", fields["plan"], "free")
+	}
+}
+
+func TestServer_pagination(t *testing.T) {
+	srv := convertkittest.NewServer("secret")
+	defer srv.Close()
+	var subs []convertkit.Subscriber
+	for i := 0; i < convertkittest.PageSize+1; i++ {
+		subs = append(subs, convertkit.Subscriber{Email: "x@example.com"})
+	}
+	srv.Seed(convertkittest.Fixtures{Subscribers: subs})
+	c := srv.Client()
+	page2, err := c.Subscribers(convertkit.SubscribersRequest{Page: 2})
+	if err != nil {
+		t.Fatalf("Subscribers() err = %v; want nil", err)
+	}
+	if page2.TotalPages != 2 || len(page2.Subscribers) != 1 {
+		t.Errorf("Subscribers(page 2) = %d pages, %d subscribers; want 2, 1", page2.TotalPages, len(page2.Subscribers))
+	}
+}
+
+func TestServer_webhooks(t *testing.T) {
+	srv := server(t)
+	c := srv.Client()
+	created, err := c.CreateWebhook(convertkit.CreateWebhookRequest{
+		TargetURL: "https://example.com/hook",
+		Event:     convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 400},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook() err = %v; want nil", err)
+	}
+	if got := srv.Webhooks(); len(got) != 1 || got[0].Event.TagID != 400 {
+		t.Errorf("Webhooks() = %+v; want one tag_add rule", got)
+	}
+	if _, err := c.DeleteWebhook(created.Rule.ID); err != nil {
+		t.Fatalf("DeleteWebhook() err = %v; want nil", err)
+	}
+	if got := srv.Webhooks(); len(got) != 0 {
+		t.Errorf("Webhooks() = %+v; want none", got)
+	}
+}
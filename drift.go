@@ -0,0 +1,212 @@
+package convertkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DriftKind describes how a response differed from the types in this package.
+type DriftKind string
+
+// DriftKinds reported by schema drift detection.
+const (
+	// DriftUnknownField means the JSON had a field that isn't modeled.
+	DriftUnknownField DriftKind = "unknown_field"
+	// DriftMissingField means a field that is expected in the JSON wasn't
+	// present. Fields tagged with omitempty and pointer fields are not expected.
+	DriftMissingField DriftKind = "missing_field"
+	// DriftTypeMismatch means a JSON value can't be decoded into the Go type of
+	// its field, eg a string where a number was expected.
+	DriftTypeMismatch DriftKind = "type_mismatch"
+)
+
+// SchemaDrift is a single difference between a JSON payload and the Go type it
+// was decoded into.
+type SchemaDrift struct {
+	Kind DriftKind
+	// Path is the location of the field in the JSON, eg "subscribers[0].state".
+	Path string
+	// Expected is the Go type of the field. It is empty for unknown fields.
+	Expected string
+	// Got is the kind of JSON value that was found (eg "string" or "object"). It
+	// is empty for missing fields.
+	Got string
+}
+
+func (sd SchemaDrift) String() string {
+	switch sd.Kind {
+	case DriftUnknownField:
+		return fmt.Sprintf("%s: unknown field (%s)", sd.Path, sd.Got)
+	case DriftMissingField:
+		return fmt.Sprintf("%s: missing field (want %s)", sd.Path, sd.Expected)
+	default:
+		return fmt.Sprintf("%s: got %s; want %s", sd.Path, sd.Got, sd.Expected)
+	}
+}
+
+// DetectSchemaDrift decodes data into v and returns every difference between
+// the JSON and v's type. A decoding error is returned if the JSON is invalid or
+// a value can't be decoded, but drifts are still reported when possible.
+//
+// This is what Client.OnSchemaDrift uses under the hood, and it can also be
+// used directly to check saved payloads against this package's types.
+func DetectSchemaDrift(data []byte, v interface{}) ([]SchemaDrift, error) {
+	err := json.Unmarshal(data, v)
+	if !json.Valid(data) {
+		return nil, err
+	}
+	return schemaDrift(data, v), err
+}
+
+func schemaDrift(data []byte, v interface{}) []SchemaDrift {
+	var drifts []SchemaDrift
+	var w jsonWalker
+	w.onObject = func(path string, raw json.RawMessage, obj map[string]json.RawMessage, unknown []string, v reflect.Value) {
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			drifts = append(drifts, SchemaDrift{
+				Kind: DriftUnknownField,
+				Path: joinPath(path, key),
+				Got:  jsonKind(obj[key]),
+			})
+		}
+	}
+	w.onMissing = func(path string, f jsonField) {
+		if f.field.Type.Kind() == reflect.Ptr || strings.Contains(f.field.Tag.Get("json"), ",omitempty") {
+			return
+		}
+		drifts = append(drifts, SchemaDrift{
+			Kind:     DriftMissingField,
+			Path:     path,
+			Expected: f.field.Type.String(),
+		})
+	}
+	w.onField = func(path string, raw json.RawMessage, f jsonField, v reflect.Value) {
+		got := jsonKind(raw)
+		if compatible(got, v.Type()) {
+			return
+		}
+		drifts = append(drifts, SchemaDrift{
+			Kind:     DriftTypeMismatch,
+			Path:     path,
+			Expected: v.Type().String(),
+			Got:      got,
+		})
+	}
+	w.walk("", data, reflect.ValueOf(v))
+	return drifts
+}
+
+// decodeSkippingMismatches decodes data into v again with every value that
+// drifts reports as a type mismatch replaced by null, so that those fields are
+// left as their zero value and everything else is decoded. It returns an error
+// if there are no mismatches to skip or decoding still fails.
+func decodeSkippingMismatches(data []byte, v interface{}, drifts []SchemaDrift) error {
+	skip := make(map[string]bool)
+	for _, d := range drifts {
+		if d.Kind == DriftTypeMismatch {
+			skip[strings.ToLower(d.Path)] = true
+		}
+	}
+	if len(skip) == 0 {
+		return errors.New("no type mismatches to skip")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	err := dec.Decode(&tree)
+	if err != nil {
+		return err
+	}
+	cleaned, err := json.Marshal(nullPaths("", tree, skip))
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+	return json.Unmarshal(cleaned, v)
+}
+
+// nullPaths replaces the values in node at the given (lowercased) paths with
+// nil.
+func nullPaths(path string, node interface{}, paths map[string]bool) interface{} {
+	if path != "" && paths[strings.ToLower(path)] {
+		return nil
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, child := range n {
+			n[key] = nullPaths(joinPath(path, key), child, paths)
+		}
+	case []interface{}:
+		for i, child := range n {
+			n[i] = nullPaths(joinPath(path, "["+strconv.Itoa(i)+"]"), child, paths)
+		}
+	}
+	return node
+}
+
+// jsonKind returns the kind of JSON value in raw.
+func jsonKind(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "invalid"
+	}
+	switch raw[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// compatible reports whether a JSON value of the given kind can be decoded
+// into t. null is treated as compatible with everything since encoding/json
+// leaves the Go value untouched.
+func compatible(kind string, t reflect.Type) bool {
+	if kind == "null" {
+		return true
+	}
+	if t == reflect.TypeOf(json.RawMessage(nil)) {
+		return true
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		// Custom decoders (time.Time, CreateTagsResponse, ...) decide for
+		// themselves, but everything we use them for expects a string, object or
+		// array rather than a number or bool.
+		return kind == "string" || kind == "object" || kind == "array"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return compatible(kind, t.Elem())
+	case reflect.Interface:
+		return true
+	case reflect.String:
+		return kind == "string"
+	case reflect.Bool:
+		return kind == "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kind == "number"
+	case reflect.Struct, reflect.Map:
+		return kind == "object"
+	case reflect.Slice, reflect.Array:
+		return kind == "array"
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+package convertkit_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// fixtureTypes maps each response fixture in testdata to the type it should be
+// decoded into. New fixtures need an entry here.
+var fixtureTypes = map[string]func() interface{}{
+	"DELETE_automations_hooks_1":     func() interface{} { return &convertkit.DeleteWebhookResponse{} },
+	"DELETE_subscribers_88_tags_71":  func() interface{} { return &convertkit.UntagSubscriberResponse{} },
+	"ERR_auth":                       func() interface{} { return &convertkit.ErrorResponse{} },
+	"GET_account":                    func() interface{} { return &convertkit.AccountResponse{} },
+	"GET_forms":                      func() interface{} { return &convertkit.FormsResponse{} },
+	"GET_forms_213_subscriptions":    func() interface{} { return &convertkit.FormSubscriptionsResponse{} },
+	"GET_sequences":                  func() interface{} { return &convertkit.SequencesResponse{} },
+	"GET_sequences_55_subscriptions": func() interface{} { return &convertkit.SequenceSubscriptionsResponse{} },
+	"GET_subscribers":                func() interface{} { return &convertkit.SubscribersResponse{} },
+	"GET_subscribers_123":            func() interface{} { return &convertkit.SubscriberResponse{} },
+	"GET_subscribers_123_tags":       func() interface{} { return &convertkit.SubscriberTagsResponse{} },
+	"GET_subscribers_page_2":         func() interface{} { return &convertkit.SubscribersResponse{} },
+	"GET_tags":                       func() interface{} { return &convertkit.TagsResponse{} },
+	"GET_tags_55_subscriptions":      func() interface{} { return &convertkit.TagSubscriptionsResponse{} },
+	"POST_automations_hooks":         func() interface{} { return &convertkit.CreateWebhookResponse{} },
+	"POST_forms_213_subscribe":       func() interface{} { return &convertkit.SubscribeToFormResponse{} },
+	"POST_sequences_55_subscribe":    func() interface{} { return &convertkit.SubscribeToSequenceResponse{} },
+	"POST_tags":                      func() interface{} { return &convertkit.CreateTagsResponse{} },
+	"POST_tags_14_subscribe":         func() interface{} { return &convertkit.TagSubscriberResponse{} },
+	"POST_tags_singular":             func() interface{} { return &convertkit.CreateTagsResponse{} },
+	"PUT_subscribers_123":            func() interface{} { return &convertkit.UpdateSubscriberResponse{} },
+	"PUT_unsubscribe":                func() interface{} { return &convertkit.UnsubscribeSubscriberResponse{} },
+}
+
+// checkFixtureSchemas runs every testdata/*.json response fixture through
+// schema drift detection and returns the drifts found, keyed by fixture name.
+// Fixtures without an entry in fixtureTypes are reported as errors.
+func checkFixtureSchemas(t *testing.T) map[string][]convertkit.SchemaDrift {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatalf("Glob() err = %v; want nil", err)
+	}
+	ret := make(map[string][]convertkit.SchemaDrift)
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if strings.Contains(name, ".") {
+			// eg GET_account.headers.json
+			continue
+		}
+		newV, ok := fixtureTypes[name]
+		if !ok {
+			t.Errorf("fixture %v has no entry in fixtureTypes", name)
+			continue
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%v) err = %v; want nil", path, err)
+		}
+		drifts, err := convertkit.DetectSchemaDrift(b, newV())
+		if err != nil {
+			t.Errorf("%v: DetectSchemaDrift() err = %v; want nil", name, err)
+		}
+		ret[name] = drifts
+	}
+	return ret
+}
+
+func TestFixtures_schemaDrift(t *testing.T) {
+	for name, drifts := range checkFixtureSchemas(t) {
+		for _, d := range drifts {
+			switch d.Kind {
+			case convertkit.DriftMissingField:
+				// Several fixtures are intentionally sparse.
+				t.Logf("%v: %v", name, d)
+			default:
+				t.Errorf("%v: %v", name, d)
+			}
+		}
+	}
+}
+
+func TestDetectSchemaDrift(t *testing.T) {
+	var sub convertkit.Subscriber
+	drifts, err := convertkit.DetectSchemaDrift([]byte(`{
+    "id": "1",
+    "first_name": "Jon",
+    "email_address": "jonsnow@example.com",
+    "created_at": "2016-02-28T08:07:00Z",
+    "fields": {},
+    "bounced": false
+  }`), &sub)
+	if err == nil {
+		t.Errorf("DetectSchemaDrift() err = nil; want decoding error")
+	}
+	want := []string{
+		"id: got string; want convertkit.SubscriberID",
+		"state: missing field (want string)",
+		"bounced: unknown field (bool)",
+	}
+	var got []string
+	for _, d := range drifts {
+		got = append(got, d.String())
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("drifts = %q; want %q", got, want)
+	}
+	if sub.FirstName != "Jon" {
+		t.Errorf("FirstName = %v; want Jon", sub.FirstName)
+	}
+}
+
+func TestClient_OnSchemaDrift(t *testing.T) {
+	c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "Acme Corp.", "primary_email_address": "you@example.com", "plan": "pro"}`)
+	})
+	var gotPath string
+	var got []convertkit.SchemaDrift
+	c.OnSchemaDrift = func(method, path string, drifts []convertkit.SchemaDrift) {
+		gotPath = method + " " + path
+		got = drifts
+	}
+	_, err := c.Account()
+	if err != nil {
+		t.Fatalf("Account() err = %v; want nil", err)
+	}
+	if gotPath != "GET account" {
+		t.Errorf("path = %v; want %v", gotPath, "GET account")
+	}
+	if len(got) != 1 || got[0].Kind != convertkit.DriftUnknownField || got[0].Path != "plan" {
+		t.Errorf("drifts = %v; want unknown field plan", got)
+	}
+
+	t.Run("type mismatches", func(t *testing.T) {
+		c := clientWithHandler(t, testdataHandler(t, "GET_subscribers_123.type_mismatch"))
+		_, err := c.Subscriber(123)
+		if err == nil {
+			t.Fatalf("Subscriber() err = nil without OnSchemaDrift; want decoding error")
+		}
+		var got []string
+		c.OnSchemaDrift = func(method, path string, drifts []convertkit.SchemaDrift) {
+			for _, d := range drifts {
+				got = append(got, d.String())
+			}
+		}
+		resp, err := c.Subscriber(123)
+		if err != nil {
+			t.Fatalf("Subscriber() err = %v; want nil", err)
+		}
+		want := []string{
+			"subscriber.id: got string; want convertkit.SubscriberID",
+			"subscriber.created_at: got number; want time.Time",
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("drifts = %q; want %q", got, want)
+		}
+		if resp.ID != 0 || !resp.CreatedAt.IsZero() {
+			t.Errorf("ID, CreatedAt = %v, %v; want zero values", resp.ID, resp.CreatedAt)
+		}
+		if resp.FirstName != "Jon" || resp.Fields["last_name"] != "Snow" {
+			t.Errorf("Subscriber = %+v; want the other fields decoded", resp.Subscriber)
+		}
+	})
+
+	t.Run("errors are unchanged", func(t *testing.T) {
+		c := client(t, "fake-secret-key")
+		c.Secret = "invalid"
+		c.OnSchemaDrift = func(method, path string, drifts []convertkit.SchemaDrift) {
+			t.Errorf("OnSchemaDrift() called for an error response")
+		}
+		_, err := c.Account()
+		var ckErr convertkit.ErrorResponse
+		if !errors.As(err, &ckErr) {
+			t.Fatalf("Account() err type = %T; want %T", err, ckErr)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package convertkit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportFormat is a file format written by an Exporter.
+type ExportFormat string
+
+// Export formats
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSONL ExportFormat = "jsonl"
+	ExportJSON  ExportFormat = "json"
+)
+
+// Exporter writes every subscriber in an account to a CSV, JSON Lines, or
+// JSON file. Subscribers are streamed a page at a time, so memory use doesn't
+// grow with the size of the list.
+//
+// CSV files have the columns id, email_address, first_name, state and
+// created_at, followed by a "fields.<key>" column for each custom field in
+// sorted order, and a tags column when Tags is set. JSON and JSON Lines
+// records have the same keys in the same order, with custom fields nested in
+// a "fields" object and tags as an array of names.
+type Exporter struct {
+	Client SubscriberAPI
+	// Format defaults to ExportCSV.
+	Format ExportFormat
+	// Request filters the subscribers exported. Page is ignored.
+	Request SubscribersRequest
+	// Fields lists the custom fields to export. When nil, every custom field
+	// is exported. CSV files need their columns up front, so a nil Fields
+	// with ExportCSV costs an extra pass over the subscriber list to find
+	// them; fields added to the account between the two passes are left out.
+	Fields []string
+	// Tags adds the names of each subscriber's tags to the export. This takes
+	// one SubscriberTags call per subscriber.
+	Tags bool
+}
+
+// exportRecord is a subscriber as written by ExportJSON and ExportJSONL.
+type exportRecord struct {
+	ID        SubscriberID      `json:"id"`
+	Email     string            `json:"email_address"`
+	FirstName string            `json:"first_name"`
+	State     string            `json:"state"`
+	CreatedAt time.Time         `json:"created_at"`
+	Fields    map[string]string `json:"fields"`
+	// Tags is a []string when Exporter.Tags is set and nil otherwise, so that
+	// subscribers without tags still get an empty array.
+	Tags interface{} `json:"tags,omitempty"`
+}
+
+// Export writes the subscribers to w and returns the number written. If an
+// error is returned, w may contain a partial export.
+func (e *Exporter) Export(w io.Writer) (int, error) {
+	switch e.Format {
+	case "", ExportCSV:
+		return e.exportCSV(w)
+	case ExportJSONL, ExportJSON:
+		return e.exportJSON(w)
+	default:
+		return 0, fmt.Errorf("exporting: unknown export format %q", e.Format)
+	}
+}
+
+func (e *Exporter) exportCSV(w io.Writer) (int, error) {
+	fields := e.Fields
+	if fields == nil {
+		var err error
+		fields, err = e.discoverFields()
+		if err != nil {
+			return 0, err
+		}
+	}
+	cw := csv.NewWriter(w)
+	header := []string{"id", "email_address", "first_name", "state", "created_at"}
+	for _, f := range fields {
+		header = append(header, "fields."+f)
+	}
+	if e.Tags {
+		header = append(header, "tags")
+	}
+	err := cw.Write(header)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	err = e.each(func(sub Subscriber, tags []string) error {
+		row := []string{
+			fmt.Sprint(sub.ID),
+			sub.Email,
+			sub.FirstName,
+			sub.State,
+			sub.CreatedAt.Format(time.RFC3339),
+		}
+		for _, f := range fields {
+			row = append(row, sub.Fields[f])
+		}
+		if e.Tags {
+			row = append(row, strings.Join(tags, ";"))
+		}
+		n++
+		return cw.Write(row)
+	})
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	return n, err
+}
+
+func (e *Exporter) exportJSON(w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	array := e.Format == ExportJSON
+	if array {
+		bw.WriteString("[")
+	}
+	n := 0
+	err := e.each(func(sub Subscriber, tags []string) error {
+		rec := exportRecord{
+			ID:        sub.ID,
+			Email:     sub.Email,
+			FirstName: sub.FirstName,
+			State:     sub.State,
+			CreatedAt: sub.CreatedAt,
+			Fields:    sub.Fields,
+		}
+		if e.Fields != nil {
+			rec.Fields = make(map[string]string, len(e.Fields))
+			for _, f := range e.Fields {
+				rec.Fields[f] = sub.Fields[f]
+			}
+		}
+		if rec.Fields == nil {
+			rec.Fields = map[string]string{}
+		}
+		if e.Tags {
+			rec.Tags = tags
+		}
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !array:
+		case n == 0:
+			bw.WriteString("\n  ")
+		default:
+			bw.WriteString(",\n  ")
+		}
+		n++
+		bw.Write(b)
+		if !array {
+			bw.WriteString("\n")
+		}
+		return nil
+	})
+	if err != nil {
+		bw.Flush()
+		return n, err
+	}
+	if array {
+		if n > 0 {
+			bw.WriteString("\n")
+		}
+		bw.WriteString("]\n")
+	}
+	return n, bw.Flush()
+}
+
+// discoverFields returns the sorted keys of every custom field used by the
+// subscribers being exported.
+func (e *Exporter) discoverFields() ([]string, error) {
+	keys := make(map[string]bool)
+	err := e.eachPage(func(subs []Subscriber) error {
+		for _, sub := range subs {
+			for k := range sub.Fields {
+				keys[k] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	for k := range keys {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// each calls fn with every subscriber being exported, along with the sorted
+// names of its tags when e.Tags is set.
+func (e *Exporter) each(fn func(sub Subscriber, tags []string) error) error {
+	return e.eachPage(func(subs []Subscriber) error {
+		for _, sub := range subs {
+			var tags []string
+			if e.Tags {
+				resp, err := e.Client.SubscriberTags(sub.ID)
+				if err != nil {
+					return fmt.Errorf("exporting: tags for subscriber %v: %w", sub.ID, err)
+				}
+				tags = []string{}
+				for _, tag := range resp.Tags {
+					tags = append(tags, tag.Name)
+				}
+				sort.Strings(tags)
+			}
+			err := fn(sub, tags)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// eachPage calls fn with each page of subscribers being exported.
+func (e *Exporter) eachPage(fn func(subs []Subscriber) error) error {
+	req := e.Request
+	req.Page = 1
+	for {
+		resp, err := e.Client.Subscribers(req)
+		if err != nil {
+			return fmt.Errorf("exporting: subscribers page %d: %w", req.Page, err)
+		}
+		err = fn(resp.Subscribers)
+		if err != nil {
+			return err
+		}
+		if resp.Page >= resp.TotalPages || len(resp.Subscribers) == 0 {
+			return nil
+		}
+		req.Page = resp.Page + 1
+	}
+}
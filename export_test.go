@@ -0,0 +1,162 @@
+package convertkit_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func exportServer(t *testing.T) *convertkit.Client {
+	t.Helper()
+	srv := convertkittest.NewServer("fake-secret-key")
+	t.Cleanup(srv.Close)
+	srv.Now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+	srv.Seed(convertkittest.Fixtures{
+		Tags: []convertkit.Tag{{ID: 14, Name: "Customer"}, {ID: 15, Name: "Beta, Early"}},
+		Subscribers: []convertkit.Subscriber{
+			{ID: 1, Email: "jon@example.com", FirstName: "Jon", Fields: map[string]string{"last_name": "Snow"}},
+			{ID: 2, Email: "arya@example.com", FirstName: "Arya", Fields: map[string]string{"city": "Braavos"}},
+		},
+	})
+	c := srv.Client()
+	for _, id := range []convertkit.TagID{15, 14} {
+		_, err := c.TagSubscriber(convertkit.TagSubscriberRequest{TagID: id, Email: "jon@example.com"})
+		if err != nil {
+			t.Fatalf("TagSubscriber() err = %v; want nil", err)
+		}
+	}
+	return c
+}
+
+func TestExporter_Export(t *testing.T) {
+	c := exportServer(t)
+	tests := map[string]struct {
+		exporter convertkit.Exporter
+		want     string
+	}{
+		"csv": {
+			exporter: convertkit.Exporter{},
+			want: "id,email_address,first_name,state,created_at,fields.city,fields.last_name\n" +
+				"1,jon@example.com,Jon,active,2020-01-02T03:04:05Z,,Snow\n" +
+				"2,arya@example.com,Arya,active,2020-01-02T03:04:05Z,Braavos,\n",
+		},
+		"csv with fields and tags": {
+			exporter: convertkit.Exporter{Fields: []string{"last_name"}, Tags: true},
+			want: "id,email_address,first_name,state,created_at,fields.last_name,tags\n" +
+				"1,jon@example.com,Jon,active,2020-01-02T03:04:05Z,Snow,\"Beta, Early;Customer\"\n" +
+				"2,arya@example.com,Arya,active,2020-01-02T03:04:05Z,,\n",
+		},
+		"jsonl": {
+			exporter: convertkit.Exporter{Format: convertkit.ExportJSONL, Tags: true},
+			want: `{"id":1,"email_address":"jon@example.com","first_name":"Jon","state":"active","created_at":"2020-01-02T03:04:05Z","fields":{"last_name":"Snow"},"tags":["Beta, Early","Customer"]}` + "\n" +
+				`{"id":2,"email_address":"arya@example.com","first_name":"Arya","state":"active","created_at":"2020-01-02T03:04:05Z","fields":{"city":"Braavos"},"tags":[]}` + "\n",
+		},
+		"json": {
+			exporter: convertkit.Exporter{Format: convertkit.ExportJSON, Fields: []string{"city"}},
+			want: "[\n" +
+				`  {"id":1,"email_address":"jon@example.com","first_name":"Jon","state":"active","created_at":"2020-01-02T03:04:05Z","fields":{"city":""}},` + "\n" +
+				`  {"id":2,"email_address":"arya@example.com","first_name":"Arya","state":"active","created_at":"2020-01-02T03:04:05Z","fields":{"city":"Braavos"}}` + "\n" +
+				"]\n",
+		},
+		"json with no subscribers": {
+			exporter: convertkit.Exporter{
+				Format:  convertkit.ExportJSON,
+				Request: convertkit.SubscribersRequest{Email: "nobody@example.com"},
+			},
+			want: "[]\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.exporter.Client = c
+			var buf bytes.Buffer
+			n, err := tc.exporter.Export(&buf)
+			if err != nil {
+				t.Fatalf("Export() err = %v; want nil", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("Export() wrote\n%s\nwant\n%s", got, tc.want)
+			}
+			if tc.exporter.Format == convertkit.ExportJSON {
+				var recs []map[string]interface{}
+				err := json.Unmarshal(buf.Bytes(), &recs)
+				if err != nil {
+					t.Errorf("Unmarshal() err = %v; want valid JSON", err)
+				}
+				if len(recs) != n {
+					t.Errorf("len(records) = %d; want %d", len(recs), n)
+				}
+			}
+		})
+	}
+}
+
+func TestExporter_Export_pages(t *testing.T) {
+	srv := convertkittest.NewServer("fake-secret-key")
+	t.Cleanup(srv.Close)
+	var subs []convertkit.Subscriber
+	for i := 1; i <= 2*convertkittest.PageSize+1; i++ {
+		subs = append(subs, convertkit.Subscriber{
+			Email:  fmt.Sprintf("sub%d@example.com", i),
+			Fields: map[string]string{fmt.Sprintf("field%d", i%3): "x"},
+		})
+	}
+	srv.Seed(convertkittest.Fixtures{Subscribers: subs})
+	var buf bytes.Buffer
+	e := convertkit.Exporter{Client: srv.Client()}
+	n, err := e.Export(&buf)
+	if err != nil {
+		t.Fatalf("Export() err = %v; want nil", err)
+	}
+	if n != len(subs) {
+		t.Errorf("Export() n = %d; want %d", n, len(subs))
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() err = %v; want nil", err)
+	}
+	if len(rows) != len(subs)+1 {
+		t.Errorf("len(rows) = %d; want %d", len(rows), len(subs)+1)
+	}
+	wantHeader := "id,email_address,first_name,state,created_at,fields.field0,fields.field1,fields.field2"
+	if got := strings.Join(rows[0], ","); got != wantHeader {
+		t.Errorf("header = %v; want %v", got, wantHeader)
+	}
+	if got := rows[len(rows)-1][1]; got != subs[len(subs)-1].Email {
+		t.Errorf("last email = %v; want %v", got, subs[len(subs)-1].Email)
+	}
+}
+
+func TestExporter_Export_errors(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		e := convertkit.Exporter{Client: &convertkittest.Mock{}, Format: "xml"}
+		_, err := e.Export(&bytes.Buffer{})
+		if err == nil {
+			t.Errorf("Export() err = nil; want an error")
+		}
+	})
+	t.Run("tags", func(t *testing.T) {
+		m := &convertkittest.Mock{
+			SubscribersFunc: func(convertkit.SubscribersRequest) (*convertkit.SubscribersResponse, error) {
+				return &convertkit.SubscribersResponse{
+					Page:        1,
+					TotalPages:  1,
+					Subscribers: []convertkit.Subscriber{{ID: 1}},
+				}, nil
+			},
+		}
+		e := convertkit.Exporter{Client: m, Format: convertkit.ExportJSONL, Tags: true}
+		_, err := e.Export(&bytes.Buffer{})
+		if !errors.Is(err, convertkittest.ErrNotStubbed) {
+			t.Errorf("Export() err = %v; want %v", err, convertkittest.ErrNotStubbed)
+		}
+	})
+}
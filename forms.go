@@ -9,7 +9,8 @@ import (
 // an HTML form, but you can subscribe someone to a form via API as well. It is
 // returned from several API endpoints.
 type Form struct {
-	ID               int       `json:"id"`
+	Raw              *RawJSON  `json:"-"`
+	ID               FormID    `json:"id"`
 	Name             string    `json:"name"`
 	CreatedAt        time.Time `json:"created_at"`
 	Type             string    `json:"type"`
@@ -24,7 +25,8 @@ type Form struct {
 
 // FormsResponse defines the data returned from a Forms API call.
 type FormsResponse struct {
-	Forms []Form `json:"forms"`
+	RawJSON `json:"-"`
+	Forms   []Form `json:"forms"`
 }
 
 // Forms lists the forms from your account.
@@ -0,0 +1,317 @@
+package convertkit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// addTestdataSeeds adds every testdata/*.json response fixture to the seed
+// corpus of f.
+func addTestdataSeeds(f *testing.F) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		f.Fatalf("Glob() err = %v; want nil", err)
+	}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatalf("ReadFile(%v) err = %v; want nil", path, err)
+		}
+		f.Add(b)
+	}
+}
+
+func FuzzCreateTagsResponse(f *testing.F) {
+	addTestdataSeeds(f)
+	f.Add([]byte(`null`))
+	f.Add([]byte(` [{"id": 1}] `))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var got convertkit.CreateTagsResponse
+		err := json.Unmarshal(b, &got)
+
+		// A response is either a list of tags or a single tag.
+		var many []convertkit.Tag
+		var one convertkit.Tag
+		var want []convertkit.Tag
+		var wantErr bool
+		switch firstByte(b) {
+		case '{':
+			wantErr = json.Unmarshal(b, &one) != nil
+			want = []convertkit.Tag{one}
+		default:
+			if json.Unmarshal(b, &many) == nil {
+				want = many
+			} else {
+				wantErr = json.Unmarshal(b, &one) != nil
+				want = []convertkit.Tag{one}
+			}
+		}
+		if (err != nil) != wantErr {
+			t.Fatalf("Unmarshal(%q) err = %v; want error: %v", b, err, wantErr)
+		}
+		if err != nil {
+			return
+		}
+		if !reflect.DeepEqual(got.Tags, want) {
+			t.Fatalf("Unmarshal(%q) Tags = %+v; want %+v", b, got.Tags, want)
+		}
+
+		// Re-encoding the tags must decode to the same tags.
+		reencoded, err := json.Marshal(got.Tags)
+		if err != nil {
+			t.Fatalf("Marshal() err = %v; want nil", err)
+		}
+		var again convertkit.CreateTagsResponse
+		err = json.Unmarshal(reencoded, &again)
+		if err != nil {
+			t.Fatalf("Unmarshal(%q) err = %v; want nil", reencoded, err)
+		}
+		if !reflect.DeepEqual(again.Tags, got.Tags) {
+			t.Fatalf("round trip Tags = %+v; want %+v", again.Tags, got.Tags)
+		}
+	})
+}
+
+func firstByte(b []byte) byte {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return 0
+	}
+	return b[0]
+}
+
+func FuzzUntagSubscriberResponse(f *testing.F) {
+	addTestdataSeeds(f)
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var want convertkit.Tag
+		wantErr := json.Unmarshal(b, &want)
+
+		var got convertkit.UntagSubscriberResponse
+		err := json.Unmarshal(b, &got)
+		if (err != nil) != (wantErr != nil) {
+			t.Fatalf("Unmarshal(%q) err = %v; want %v", b, err, wantErr)
+		}
+		if err == nil && !reflect.DeepEqual(got.Tag, want) {
+			t.Fatalf("Unmarshal(%q) Tag = %+v; want %+v", b, got.Tag, want)
+		}
+
+		if err != nil {
+			return
+		}
+		// The client must return the decoded tag too.
+		c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(b)
+		})
+		resp, err := c.UntagSubscriber(convertkit.UntagSubscriberRequest{SubscriberID: 1, TagID: 2})
+		if err != nil {
+			t.Fatalf("UntagSubscriber() err = %v; want nil", err)
+		}
+		if !reflect.DeepEqual(resp.Tag, want) {
+			t.Fatalf("UntagSubscriber() Tag = %+v; want %+v", resp.Tag, want)
+		}
+	})
+}
+
+// requestRecorder is an HTTPClient that records requests instead of sending
+// them.
+type requestRecorder struct {
+	req  *http.Request
+	body []byte
+}
+
+func (rr *requestRecorder) Do(r *http.Request) (*http.Response, error) {
+	rr.req = r
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		rr.body = b
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte("{}"))),
+	}, nil
+}
+
+type fuzzParams struct {
+	Page int64             `json:"page,omitempty"`
+	ID   int64             `json:"id"`
+	Name string            `json:"name"`
+	Tags []int64           `json:"tags,omitempty"`
+	Meta map[string]string `json:"fields,omitempty"`
+}
+
+// FuzzDoParams checks that params round trip through the query string and
+// JSON body built by Do.
+func FuzzDoParams(f *testing.F) {
+	f.Add(int64(2), int64(213), "Jon Snow", "last_name", "Snow")
+	f.Add(int64(10000000), int64(9007199254740993), "", "", "")
+	f.Add(int64(-1), int64(0), "a&b=c", "ü", "\x00")
+	f.Fuzz(func(t *testing.T, page, id int64, name, key, value string) {
+		if !utf8.ValidString(name) || !utf8.ValidString(key) || !utf8.ValidString(value) {
+			// encoding/json replaces invalid UTF-8, so it can't round trip.
+			t.Skip()
+		}
+		params := fuzzParams{Page: page, ID: id, Name: name, Tags: []int64{id, page}}
+		if key != "" {
+			params.Meta = map[string]string{key: value}
+		}
+
+		var rr requestRecorder
+		c := &convertkit.Client{Secret: "secret", HTTPClient: &rr}
+		err := c.Do(http.MethodGet, "subscribers", params, &struct{}{})
+		if err != nil {
+			t.Fatalf("Do(GET) err = %v; want nil", err)
+		}
+		query := rr.req.URL.Query()
+		if got := query.Get("id"); got != strconv.FormatInt(id, 10) {
+			t.Errorf("id = %q; want %d", got, id)
+		}
+		if got := query.Get("name"); got != name {
+			t.Errorf("name = %q; want %q", got, name)
+		}
+		if page != 0 {
+			if got := query.Get("page"); got != strconv.FormatInt(page, 10) {
+				t.Errorf("page = %q; want %d", got, page)
+			}
+		}
+
+		err = c.Do(http.MethodPost, "subscribers", params, &struct{}{})
+		if err != nil {
+			t.Fatalf("Do(POST) err = %v; want nil", err)
+		}
+		var got fuzzParams
+		err = json.Unmarshal(rr.body, &got)
+		if err != nil {
+			t.Fatalf("Unmarshal(%q) err = %v; want nil", rr.body, err)
+		}
+		if !reflect.DeepEqual(got, params) {
+			t.Errorf("body = %+v; want %+v", got, params)
+		}
+	})
+}
+
+func FuzzDate(f *testing.F) {
+	f.Add(2020, 2, 29)
+	f.Add(999, 1, 1)
+	f.Add(0, 12, 31)
+	f.Fuzz(func(t *testing.T, year, month, day int) {
+		d := convertkit.NewDate(year, month, day)
+		if y := time.Time(d).Year(); y < 0 || y > 9999 {
+			// Dates outside of yyyy can't be formatted as yyyy-mm-dd.
+			t.Skip()
+		}
+		want, err := json.Marshal(time.Time(d).Format("2006-01-02"))
+		if err != nil {
+			t.Fatalf("Marshal() err = %v; want nil", err)
+		}
+
+		got, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("Marshal(Date) err = %v; want nil", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("Marshal(Date) = %s; want %s", got, want)
+		}
+		got, err = json.Marshal(&d)
+		if err != nil {
+			t.Fatalf("Marshal(*Date) err = %v; want nil", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("Marshal(*Date) = %s; want %s", got, want)
+		}
+
+		var parsed convertkit.Date
+		err = json.Unmarshal(got, &parsed)
+		if err != nil {
+			t.Fatalf("Unmarshal(%s) err = %v; want nil", got, err)
+		}
+		if !time.Time(parsed).Equal(time.Time(d)) {
+			t.Fatalf("Unmarshal(%s) = %v; want %v", got, time.Time(parsed), time.Time(d))
+		}
+	})
+}
+
+func FuzzDateUnmarshal(f *testing.F) {
+	f.Add([]byte(`"2020-01-02"`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`"2020-02-30"`))
+	f.Add([]byte(`20200102`))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var d convertkit.Date
+		err := json.Unmarshal(b, &d)
+		if err != nil {
+			return
+		}
+		got, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("Marshal() err = %v; want nil", err)
+		}
+		var again convertkit.Date
+		err = json.Unmarshal(got, &again)
+		if err != nil {
+			t.Fatalf("Unmarshal(%s) err = %v; want nil", got, err)
+		}
+		if !time.Time(again).Equal(time.Time(d)) {
+			t.Fatalf("round trip of %s = %v; want %v", b, time.Time(again), time.Time(d))
+		}
+	})
+}
+
+func TestDate_MarshalJSON(t *testing.T) {
+	type params struct {
+		Value   convertkit.Date  `json:"value"`
+		Pointer *convertkit.Date `json:"pointer"`
+	}
+	d := convertkit.NewDate(999, 3, 4)
+	tests := map[string]struct {
+		v    interface{}
+		want string
+	}{
+		"value":   {v: d, want: `"0999-03-04"`},
+		"pointer": {v: &d, want: `"0999-03-04"`},
+		"non-addressable field": {
+			v:    params{Value: d},
+			want: `{"value":"0999-03-04","pointer":null}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(tc.v)
+			if err != nil {
+				t.Fatalf("Marshal() err = %v; want nil", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Marshal() = %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDate_UnmarshalJSON(t *testing.T) {
+	var d convertkit.Date
+	err := json.Unmarshal([]byte(`"2019-05-05"`), &d)
+	if err != nil {
+		t.Fatalf("Unmarshal() err = %v; want nil", err)
+	}
+	if want := convertkit.NewDate(2019, 5, 5); !time.Time(d).Equal(time.Time(want)) {
+		t.Errorf("Unmarshal() = %v; want %v", time.Time(d), time.Time(want))
+	}
+	err = json.Unmarshal([]byte(`"May 5th"`), &d)
+	var syntaxErr *time.ParseError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Unmarshal() err = %v; want a *time.ParseError", err)
+	}
+}
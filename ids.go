@@ -0,0 +1,42 @@
+package convertkit
+
+// Resource IDs are all integers as far as the Convert Kit API is concerned, but
+// mixing them up is an easy mistake to make (eg passing a tag ID where a form ID
+// was expected). Each resource gets its own ID type so the compiler can catch
+// these mistakes for us.
+//
+// All of these types are defined as ints, so they encode to JSON and print via
+// fmt exactly the same way a plain int would. Untyped constants (eg
+// `FormID: 213`) continue to work as-is, and existing int values can be
+// migrated with a simple conversion like `convertkit.FormID(id)`.
+
+// FormID is the unique ID of a Form.
+type FormID int
+
+// TagID is the unique ID of a Tag.
+type TagID int
+
+// SequenceID is the unique ID of a Sequence.
+type SequenceID int
+
+// SubscriberID is the unique ID of a Subscriber.
+type SubscriberID int
+
+// BroadcastID is the unique ID of a broadcast.
+type BroadcastID int
+
+// WebhookRuleID is the unique ID of a webhook rule.
+type WebhookRuleID int
+
+// TagIDs converts a slice of ints into a slice of TagIDs. This is mostly
+// useful when migrating code that stored tag IDs as []int.
+func TagIDs(ids ...int) []TagID {
+	if ids == nil {
+		return nil
+	}
+	ret := make([]TagID, len(ids))
+	for i, id := range ids {
+		ret[i] = TagID(id)
+	}
+	return ret
+}
@@ -0,0 +1,55 @@
+package convertkit_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func TestIDs_encodeLikeInts(t *testing.T) {
+	for name, id := range map[string]interface{}{
+		"FormID":        convertkit.FormID(213),
+		"TagID":         convertkit.TagID(213),
+		"SequenceID":    convertkit.SequenceID(213),
+		"SubscriberID":  convertkit.SubscriberID(213),
+		"BroadcastID":   convertkit.BroadcastID(213),
+		"WebhookRuleID": convertkit.WebhookRuleID(213),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(id)
+			if err != nil {
+				t.Fatalf("Marshal() err = %v; want nil", err)
+			}
+			if string(got) != "213" {
+				t.Errorf("Marshal() = %s; want 213", got)
+			}
+			if got := fmt.Sprintf("%v", id); got != "213" {
+				t.Errorf("Sprintf(%%v) = %v; want 213", got)
+			}
+		})
+	}
+}
+
+func TestSubscribeToFormRequest_tagIDs(t *testing.T) {
+	req := convertkit.SubscribeToFormRequest{
+		FormID: 213,
+		Email:  "jonsnow@example.com",
+		TagIDs: convertkit.TagIDs(1, 2),
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal() err = %v; want nil", err)
+	}
+	var got map[string]interface{}
+	err = json.Unmarshal(b, &got)
+	if err != nil {
+		t.Fatalf("Unmarshal() err = %v; want nil", err)
+	}
+	want := []interface{}{1.0, 2.0}
+	if !reflect.DeepEqual(got["tags"], want) {
+		t.Errorf("tags = %v; want %v", got["tags"], want)
+	}
+}
@@ -0,0 +1,634 @@
+package convertkit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joncalhoun/convertkit/internal/atomicfile"
+)
+
+// ImportFormat is a file format read by an Importer.
+type ImportFormat string
+
+// Import formats
+const (
+	ImportCSV   ImportFormat = "csv"
+	ImportJSONL ImportFormat = "jsonl"
+)
+
+// ImportAPI is the set of Client methods used by an Importer.
+type ImportAPI interface {
+	TagAPI
+	FormAPI
+	SequenceAPI
+}
+
+// ColumnMapping says which columns of an import file hold each part of a
+// subscriber. The defaults match the files written by Exporter, and columns
+// left at their default don't need to be in the file.
+type ColumnMapping struct {
+	// Email defaults to "email_address".
+	Email string
+	// FirstName defaults to "first_name".
+	FirstName string
+	// Fields maps custom field keys to the columns holding their values. When
+	// nil, every column named "fields.<key>" is used. Empty values are
+	// skipped so that an import never clears a custom field.
+	Fields map[string]string
+	// Tags defaults to "tags". Tags are separated by TagSeparator and may be
+	// tag IDs or names; values made up of only digits are treated as IDs.
+	// Names are matched case-insensitively. In JSON Lines files the column
+	// may also be an array.
+	Tags string
+	// TagSeparator defaults to ";".
+	TagSeparator string
+}
+
+func (m ColumnMapping) withDefaults() ColumnMapping {
+	if m.Email == "" {
+		m.Email = "email_address"
+	}
+	if m.FirstName == "" {
+		m.FirstName = "first_name"
+	}
+	if m.Tags == "" {
+		m.Tags = "tags"
+	}
+	if m.TagSeparator == "" {
+		m.TagSeparator = ";"
+	}
+	return m
+}
+
+// ImportTarget is what imported subscribers are subscribed to. Exactly one ID
+// must be set.
+type ImportTarget struct {
+	FormID     FormID
+	SequenceID SequenceID
+	TagID      TagID
+}
+
+// Validate checks that exactly one ID is set.
+func (t ImportTarget) Validate() error {
+	n := 0
+	for _, id := range []int{int(t.FormID), int(t.SequenceID), int(t.TagID)} {
+		if id != 0 {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("importing: exactly one of FormID, SequenceID or TagID is required")
+	}
+	return nil
+}
+
+// ImportSummary is the result of an Import.
+type ImportSummary struct {
+	DryRun bool
+	// Rows is the number of rows read, including skipped rows.
+	Rows int
+	// Skipped is the number of rows skipped because the checkpoint says they
+	// were processed by an earlier run.
+	Skipped int
+	// Imported is the number of rows subscribed, or that would have been in a
+	// dry run.
+	Imported int
+	// Failed is the number of rows written to the errors file.
+	Failed int
+}
+
+func (s ImportSummary) String() string {
+	imported := "imported"
+	if s.DryRun {
+		imported = "would be imported"
+	}
+	return fmt.Sprintf("%d rows: %d %s, %d failed, %d skipped", s.Rows, s.Imported, imported, s.Failed, s.Skipped)
+}
+
+// Importer subscribes every row of a CSV or JSON Lines file to a form,
+// sequence or tag. Rows are subscribed by a pool of workers that share a
+// RateLimiter.
+//
+// If Checkpoint is set, progress is saved to it as rows are processed, and
+// rows it says are done are skipped, so an interrupted import can be resumed
+// by running it again with the same file. Rows that fail are not retried on
+// resume; they are listed in the Errors file instead. Delete the checkpoint
+// to start over.
+type Importer struct {
+	Client ImportAPI
+	// Format defaults to ImportCSV.
+	Format  ImportFormat
+	Mapping ColumnMapping
+	Target  ImportTarget
+	// Workers defaults to 4.
+	Workers int
+	// Limiter defaults to Client.RateLimiter() when Client is a *Client, so
+	// imports and bulk operations made with the same Client share a limit.
+	// Otherwise each Import gets its own limiter allowing DefaultRateLimit
+	// requests per minute. Share one RateLimiter between everything using the
+	// same API secret.
+	Limiter *RateLimiter
+	// DryRun checks every row, including resolving tag names, without
+	// subscribing anyone. Failed rows are still written to Errors.
+	DryRun bool
+	// Checkpoint is the path of the progress file. It is not written in a
+	// dry run.
+	Checkpoint string
+	// Errors, if not nil, receives a CSV file with a row, email_address and
+	// error column for each failed row. Row numbers start at 1 for the first
+	// row after the header. When the error is an ErrorResponse its message
+	// is used. The header is only written when not resuming from a
+	// checkpoint, so the file can be appended to.
+	Errors io.Writer
+
+	// tags maps lowercased tag names to tags. It is only used by the
+	// goroutine reading rows.
+	tags map[string][]Tag
+}
+
+// checkpointEvery is how many rows are processed between checkpoint saves.
+const checkpointEvery = 50
+
+type importJob struct {
+	row   int
+	email string
+	// subscribe is nil when the row is invalid, in which case err is set.
+	subscribe func() error
+	err       error
+}
+
+type importResult struct {
+	row   int
+	email string
+	err   error
+}
+
+// Import reads rows from r and subscribes them. If ctx is cancelled, rows that
+// haven't started are left for the next run and ctx.Err() is returned along
+// with the summary so far.
+func (im *Importer) Import(ctx context.Context, r io.Reader) (*ImportSummary, error) {
+	err := im.Target.Validate()
+	if err != nil {
+		return nil, err
+	}
+	m := im.Mapping.withDefaults()
+	var rows rowReader
+	switch im.Format {
+	case "", ImportCSV:
+		rows, err = newCSVRows(r, m)
+	case ImportJSONL:
+		rows = newJSONLRows(r)
+	default:
+		err = fmt.Errorf("importing: unknown import format %q", im.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resume, err := im.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	limiter := im.Limiter
+	if c, ok := im.Client.(*Client); ok && limiter == nil {
+		limiter = c.RateLimiter()
+	}
+	if limiter == nil {
+		limiter = NewRateLimiter(DefaultRateLimit, time.Minute)
+	}
+	workers := im.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	var errs *csv.Writer
+	if im.Errors != nil {
+		errs = csv.NewWriter(im.Errors)
+		if resume == 0 {
+			errs.Write([]string{"row", "email_address", "error"})
+		}
+	}
+
+	sum := ImportSummary{DryRun: im.DryRun}
+	jobs := make(chan importJob)
+	results := make(chan importResult)
+	var readErr error
+	var rowCount, skipped int
+	go func() {
+		defer close(jobs)
+		rowCount, skipped, readErr = im.produce(ctx, rows, m, resume, jobs)
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				res := importResult{row: job.row, email: job.email, err: job.err}
+				if job.subscribe != nil && !im.DryRun {
+					// Jobs that haven't started when ctx is done are dropped
+					// so that the checkpoint leaves them for the next run.
+					if limiter.Wait(ctx) != nil {
+						continue
+					}
+					res.err = job.subscribe()
+				}
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	p, saved := progress{n: resume}, resume
+	var saveErr error
+	for res := range results {
+		if res.err != nil {
+			sum.Failed++
+			if errs != nil {
+				errs.Write([]string{strconv.Itoa(res.row), res.email, importErrorMessage(res.err)})
+			}
+		} else {
+			sum.Imported++
+		}
+		p.finish(res.row)
+		if p.n-saved >= checkpointEvery && saveErr == nil {
+			saveErr = im.saveCheckpoint(errs, p.n)
+			saved = p.n
+		}
+	}
+	sum.Rows, sum.Skipped = rowCount, skipped
+	if p.n != saved && saveErr == nil {
+		saveErr = im.saveCheckpoint(errs, p.n)
+	}
+	if errs != nil {
+		errs.Flush()
+		if err := errs.Error(); err != nil {
+			return &sum, fmt.Errorf("importing: writing errors: %w", err)
+		}
+	}
+	switch {
+	case readErr != nil:
+		return &sum, readErr
+	case saveErr != nil:
+		return &sum, saveErr
+	case ctx.Err() != nil:
+		return &sum, ctx.Err()
+	}
+	return &sum, nil
+}
+
+// produce reads rows, turns them into jobs, and returns the number of rows read
+// and skipped.
+func (im *Importer) produce(ctx context.Context, rows rowReader, m ColumnMapping, resume int, jobs chan<- importJob) (int, int, error) {
+	n, skipped := 0, 0
+	for {
+		row, err := rows.next()
+		if err == io.EOF {
+			return n, skipped, nil
+		}
+		n++
+		if err != nil {
+			var rowErr importRowError
+			if !errors.As(err, &rowErr) {
+				return n, skipped, fmt.Errorf("importing: row %d: %w", n, err)
+			}
+		}
+		if n <= resume {
+			skipped++
+			continue
+		}
+		job := importJob{row: n, err: err}
+		if err == nil {
+			job.email = row.values[m.Email]
+			job.subscribe, job.err = im.job(row, m)
+			if errors.Is(job.err, errTagLookup) {
+				return n, skipped, job.err
+			}
+		}
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			return n, skipped, nil
+		}
+	}
+}
+
+// errTagLookup wraps errors listing tags, which stop the import rather than
+// failing a single row.
+var errTagLookup = errors.New("importing: listing tags")
+
+// job validates a row and returns a func that subscribes it.
+func (im *Importer) job(row importRow, m ColumnMapping) (func() error, error) {
+	email := strings.TrimSpace(row.values[m.Email])
+	firstName := strings.TrimSpace(row.values[m.FirstName])
+	fields := make(map[string]string)
+	if m.Fields == nil {
+		for col, v := range row.values {
+			if strings.HasPrefix(col, "fields.") && v != "" {
+				fields[strings.TrimPrefix(col, "fields.")] = v
+			}
+		}
+	}
+	for key, col := range m.Fields {
+		if v := row.values[col]; v != "" {
+			fields[key] = v
+		}
+	}
+	if len(fields) == 0 {
+		fields = nil
+	}
+	tags, ok := row.lists[m.Tags]
+	if !ok {
+		tags = strings.Split(row.values[m.Tags], m.TagSeparator)
+	}
+	tagIDs, err := im.resolveTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	t := im.Target
+	var req validatable
+	var subscribe func() error
+	switch {
+	case t.FormID != 0:
+		r := SubscribeToFormRequest{FormID: t.FormID, Email: email, FirstName: firstName, Fields: fields, TagIDs: tagIDs}
+		req, subscribe = r, func() error {
+			_, err := im.Client.SubscribeToForm(r)
+			return err
+		}
+	case t.SequenceID != 0:
+		r := SubscribeToSequenceRequest{SequenceID: t.SequenceID, Email: email, FirstName: firstName, Fields: fields, TagIDs: tagIDs}
+		req, subscribe = r, func() error {
+			_, err := im.Client.SubscribeToSequence(r)
+			return err
+		}
+	default:
+		r := TagSubscriberRequest{TagID: t.TagID, Email: email, FirstName: firstName, Fields: fields, TagIDs: tagIDs}
+		req, subscribe = r, func() error {
+			_, err := im.Client.TagSubscriber(r)
+			return err
+		}
+	}
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return subscribe, nil
+}
+
+// resolveTags turns tag IDs and names into IDs. Tags are listed the first time
+// a name is seen.
+func (im *Importer) resolveTags(tags []string) ([]TagID, error) {
+	var ids []TagID
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(t); err == nil && n > 0 {
+			ids = append(ids, TagID(n))
+			continue
+		}
+		if im.tags == nil {
+			resp, err := im.Client.Tags()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errTagLookup, err)
+			}
+			im.tags = make(map[string][]Tag)
+			for _, tag := range resp.Tags {
+				key := strings.ToLower(tag.Name)
+				im.tags[key] = append(im.tags[key], tag)
+			}
+		}
+		matches := im.tags[strings.ToLower(t)]
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("unknown tag %q", t)
+		case 1:
+			ids = append(ids, matches[0].ID)
+		default:
+			var matchIDs []string
+			for _, tag := range matches {
+				matchIDs = append(matchIDs, fmt.Sprint(tag.ID))
+			}
+			return nil, fmt.Errorf("ambiguous tag %q matches IDs %s", t, strings.Join(matchIDs, ", "))
+		}
+	}
+	return ids, nil
+}
+
+// importErrorMessage returns the message written to the errors file.
+func importErrorMessage(err error) string {
+	var ckErr ErrorResponse
+	if errors.As(err, &ckErr) && ckErr.Message != "" {
+		return ckErr.Message
+	}
+	return err.Error()
+}
+
+// importCheckpoint is the contents of a checkpoint file.
+type importCheckpoint struct {
+	// Row is the last row such that it and every row before it have been
+	// processed.
+	Row int `json:"row"`
+}
+
+func (im *Importer) loadCheckpoint() (int, error) {
+	if im.Checkpoint == "" {
+		return 0, nil
+	}
+	b, err := ioutil.ReadFile(im.Checkpoint)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("importing: reading checkpoint: %w", err)
+	}
+	var cp importCheckpoint
+	err = json.Unmarshal(b, &cp)
+	if err != nil {
+		return 0, fmt.Errorf("importing: decoding checkpoint: %w", err)
+	}
+	return cp.Row, nil
+}
+
+// saveCheckpoint flushes the errors file, so that it never falls behind the
+// checkpoint, then writes the checkpoint.
+func (im *Importer) saveCheckpoint(errs *csv.Writer, row int) error {
+	if im.Checkpoint == "" || im.DryRun {
+		return nil
+	}
+	if errs != nil {
+		errs.Flush()
+		if err := errs.Error(); err != nil {
+			return fmt.Errorf("importing: writing errors: %w", err)
+		}
+	}
+	b, err := json.Marshal(importCheckpoint{Row: row})
+	if err != nil {
+		return fmt.Errorf("importing: encoding checkpoint: %w", err)
+	}
+	err = atomicfile.Write(im.Checkpoint, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("importing: saving checkpoint: %w", err)
+	}
+	return nil
+}
+
+// importRow is a single row of an import file.
+type importRow struct {
+	values map[string]string
+	// lists holds the columns of a JSON Lines row that are arrays.
+	lists map[string][]string
+}
+
+// importRowError is returned by a rowReader for a row that can't be imported
+// but doesn't stop the rows after it from being read.
+type importRowError struct {
+	msg string
+}
+
+func (e importRowError) Error() string {
+	return e.msg
+}
+
+type rowReader interface {
+	// next returns the next row, or io.EOF when there are no more.
+	next() (importRow, error)
+}
+
+type csvRows struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVRows(r io.Reader, m ColumnMapping) (*csvRows, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("importing: empty file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("importing: reading header: %w", err)
+	}
+	has := make(map[string]bool)
+	for i, col := range header {
+		col = strings.TrimSpace(col)
+		if i == 0 {
+			// Spreadsheets often start CSV files with a byte order mark.
+			col = strings.TrimPrefix(col, "\ufeff")
+		}
+		header[i] = col
+		has[col] = true
+	}
+	defaults := ColumnMapping{}.withDefaults()
+	required := []string{m.Email}
+	if m.FirstName != defaults.FirstName {
+		required = append(required, m.FirstName)
+	}
+	if m.Tags != defaults.Tags {
+		required = append(required, m.Tags)
+	}
+	for _, col := range m.Fields {
+		required = append(required, col)
+	}
+	for _, col := range required {
+		if !has[col] {
+			return nil, fmt.Errorf("importing: missing column %q", col)
+		}
+	}
+	return &csvRows{r: cr, header: header}, nil
+}
+
+func (c *csvRows) next() (importRow, error) {
+	rec, err := c.r.Read()
+	if err != nil {
+		return importRow{}, err
+	}
+	if len(rec) != len(c.header) {
+		return importRow{}, importRowError{fmt.Sprintf("got %d columns; want %d", len(rec), len(c.header))}
+	}
+	row := importRow{values: make(map[string]string, len(rec))}
+	for i, v := range rec {
+		row.values[c.header[i]] = v
+	}
+	return row, nil
+}
+
+type jsonlRows struct {
+	s *bufio.Scanner
+}
+
+func newJSONLRows(r io.Reader) *jsonlRows {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+	return &jsonlRows{s: s}
+}
+
+func (j *jsonlRows) next() (importRow, error) {
+	var line []byte
+	for len(line) == 0 {
+		if !j.s.Scan() {
+			if err := j.s.Err(); err != nil {
+				return importRow{}, err
+			}
+			return importRow{}, io.EOF
+		}
+		line = bytes.TrimSpace(j.s.Bytes())
+	}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	err := dec.Decode(&obj)
+	if err != nil {
+		return importRow{}, importRowError{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	row := importRow{values: make(map[string]string, len(obj))}
+	for k, v := range obj {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			// Nested objects, eg the "fields" written by Exporter, are
+			// flattened to "fields.<key>".
+			for kk, vv := range v {
+				row.values[k+"."+kk] = jsonString(vv)
+			}
+		case []interface{}:
+			var list []string
+			for _, vv := range v {
+				list = append(list, jsonString(vv))
+			}
+			row.values[k] = strings.Join(list, ";")
+			if row.lists == nil {
+				row.lists = make(map[string][]string)
+			}
+			row.lists[k] = list
+		default:
+			row.values[k] = jsonString(v)
+		}
+	}
+	return row, nil
+}
+
+func jsonString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
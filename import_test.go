@@ -0,0 +1,279 @@
+package convertkit_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func importServer(t *testing.T) *convertkittest.Server {
+	t.Helper()
+	srv := convertkittest.NewServer("fake-secret-key")
+	t.Cleanup(srv.Close)
+	srv.Seed(convertkittest.Fixtures{
+		Forms: []convertkit.Form{{ID: 213, Name: "Newsletter"}},
+		Tags: []convertkit.Tag{
+			{ID: 14, Name: "Customer"},
+			{ID: 15, Name: "Beta"},
+			{ID: 16, Name: "Dupe"},
+			{ID: 17, Name: "dupe"},
+		},
+	})
+	return srv
+}
+
+func TestImporter_Import(t *testing.T) {
+	tests := map[string]struct {
+		importer convertkit.Importer
+		input    string
+		want     convertkit.ImportSummary
+		errors   string
+		// emails that should be subscribed, with their sorted tag IDs
+		subscribed map[string][]convertkit.TagID
+	}{
+		"csv": {
+			importer: convertkit.Importer{},
+			input: "\ufeffemail_address,first_name,fields.last_name,tags\n" +
+				"jon@example.com,Jon,Snow,customer;15\n" +
+				"arya@example.com,Arya,,\n",
+			want: convertkit.ImportSummary{Rows: 2, Imported: 2},
+			subscribed: map[string][]convertkit.TagID{
+				"jon@example.com":  {14, 15},
+				"arya@example.com": nil,
+			},
+		},
+		"mapping": {
+			importer: convertkit.Importer{
+				Mapping: convertkit.ColumnMapping{
+					Email:        "Email",
+					FirstName:    "Name",
+					Fields:       map[string]string{"last_name": "Surname"},
+					Tags:         "Labels",
+					TagSeparator: "|",
+				},
+			},
+			input: "Email,Name,Surname,Labels\n" +
+				"jon@example.com,Jon,Snow,Customer|Beta\n",
+			want: convertkit.ImportSummary{Rows: 1, Imported: 1},
+			subscribed: map[string][]convertkit.TagID{
+				"jon@example.com": {14, 15},
+			},
+		},
+		"jsonl": {
+			importer: convertkit.Importer{Format: convertkit.ImportJSONL},
+			input: `{"email_address":"jon@example.com","first_name":"Jon","fields":{"last_name":"Snow"},"tags":["Customer",15]}` + "\n\n" +
+				`{"email_address":"arya@example.com","tags":"Beta"}` + "\n",
+			want: convertkit.ImportSummary{Rows: 2, Imported: 2},
+			subscribed: map[string][]convertkit.TagID{
+				"jon@example.com":  {14, 15},
+				"arya@example.com": {15},
+			},
+		},
+		"failed rows": {
+			// One worker keeps the errors file in row order.
+			importer: convertkit.Importer{Format: convertkit.ImportJSONL, Workers: 1},
+			input: `{"email_address":"not-an-email"}` + "\n" +
+				`{"email_address":"jon@example.com","tags":"Nope"}` + "\n" +
+				`{"email_address":"arya@example.com","tags":"DUPE"}` + "\n" +
+				`{"email_address":` + "\n" +
+				`{"email_address":"sansa@example.com"}` + "\n",
+			want: convertkit.ImportSummary{Rows: 5, Imported: 1, Failed: 4},
+			errors: "row,email_address,error\n" +
+				"1,not-an-email,\"invalid request: Email \"\"not-an-email\"\" is not a valid email address\"\n" +
+				"2,jon@example.com,\"unknown tag \"\"Nope\"\"\"\n" +
+				"3,arya@example.com,\"ambiguous tag \"\"DUPE\"\" matches IDs 16, 17\"\n" +
+				"4,,invalid JSON: unexpected EOF\n",
+			subscribed: map[string][]convertkit.TagID{
+				"sansa@example.com": nil,
+			},
+		},
+		"dry run": {
+			importer: convertkit.Importer{DryRun: true},
+			input: "email_address,tags\n" +
+				"jon@example.com,Customer\n" +
+				"arya@example.com,Nope\n",
+			want:   convertkit.ImportSummary{DryRun: true, Rows: 2, Imported: 1, Failed: 1},
+			errors: "row,email_address,error\n2,arya@example.com,\"unknown tag \"\"Nope\"\"\"\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := importServer(t)
+			var errs bytes.Buffer
+			im := tc.importer
+			im.Client = srv.Client()
+			im.Target = convertkit.ImportTarget{FormID: 213}
+			im.Errors = &errs
+			got, err := im.Import(context.Background(), strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("Import() err = %v; want nil", err)
+			}
+			if *got != tc.want {
+				t.Errorf("Import() = %+v; want %+v", *got, tc.want)
+			}
+			wantErrs := tc.errors
+			if wantErrs == "" {
+				wantErrs = "row,email_address,error\n"
+			}
+			if errs.String() != wantErrs {
+				t.Errorf("errors =\n%s\nwant\n%s", errs.String(), wantErrs)
+			}
+			subs := srv.Subscribers()
+			if len(subs) != len(tc.subscribed) {
+				t.Errorf("len(Subscribers()) = %d; want %d", len(subs), len(tc.subscribed))
+			}
+			for _, sub := range subs {
+				wantTags, ok := tc.subscribed[sub.Email]
+				if !ok {
+					t.Errorf("%s subscribed; want it skipped", sub.Email)
+					continue
+				}
+				gotTags := srv.SubscriberTags(sub.ID)
+				sort.Slice(gotTags, func(i, j int) bool { return gotTags[i] < gotTags[j] })
+				if fmt.Sprint(gotTags) != fmt.Sprint(wantTags) {
+					t.Errorf("%s tags = %v; want %v", sub.Email, gotTags, wantTags)
+				}
+			}
+		})
+	}
+}
+
+func TestImporter_Import_errorResponse(t *testing.T) {
+	m := &convertkittest.Mock{
+		TagSubscriberFunc: func(convertkit.TagSubscriberRequest) (*convertkit.TagSubscriberResponse, error) {
+			return nil, convertkit.ErrorResponse{StatusCode: 422, Type: "Unprocessable", Message: "Email address is on the suppression list"}
+		},
+	}
+	var errs bytes.Buffer
+	im := convertkit.Importer{
+		Client: m,
+		Target: convertkit.ImportTarget{TagID: 14},
+		Errors: &errs,
+	}
+	sum, err := im.Import(context.Background(), strings.NewReader("email_address\njon@example.com\n"))
+	if err != nil {
+		t.Fatalf("Import() err = %v; want nil", err)
+	}
+	if sum.Failed != 1 {
+		t.Errorf("Failed = %d; want 1", sum.Failed)
+	}
+	want := "row,email_address,error\n1,jon@example.com,Email address is on the suppression list\n"
+	if errs.String() != want {
+		t.Errorf("errors = %q; want %q", errs.String(), want)
+	}
+}
+
+func TestImporter_Import_checkpoint(t *testing.T) {
+	srv := importServer(t)
+	checkpoint := filepath.Join(t.TempDir(), "import.checkpoint")
+	err := ioutil.WriteFile(checkpoint, []byte(`{"row":2}`), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() err = %v; want nil", err)
+	}
+	var errs bytes.Buffer
+	im := convertkit.Importer{
+		Client:     srv.Client(),
+		Target:     convertkit.ImportTarget{FormID: 213},
+		Checkpoint: checkpoint,
+		Errors:     &errs,
+	}
+	input := "email_address\njon@example.com\narya@example.com\nsansa@example.com\n"
+	sum, err := im.Import(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Import() err = %v; want nil", err)
+	}
+	want := convertkit.ImportSummary{Rows: 3, Skipped: 2, Imported: 1}
+	if *sum != want {
+		t.Errorf("Import() = %+v; want %+v", *sum, want)
+	}
+	if subs := srv.Subscribers(); len(subs) != 1 || subs[0].Email != "sansa@example.com" {
+		t.Errorf("Subscribers() = %v; want only sansa@example.com", subs)
+	}
+	if errs.Len() != 0 {
+		t.Errorf("errors = %q; want no header when resuming", errs.String())
+	}
+	b, err := ioutil.ReadFile(checkpoint)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	if string(b) != `{"row":3}` {
+		t.Errorf("checkpoint = %s; want %s", b, `{"row":3}`)
+	}
+}
+
+func TestImporter_Import_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	m := &convertkittest.Mock{
+		SubscribeToSequenceFunc: func(convertkit.SubscribeToSequenceRequest) (*convertkit.SubscribeToSequenceResponse, error) {
+			calls++
+			cancel()
+			return &convertkit.SubscribeToSequenceResponse{}, nil
+		},
+	}
+	checkpoint := filepath.Join(t.TempDir(), "import.checkpoint")
+	im := convertkit.Importer{
+		Client:     m,
+		Target:     convertkit.ImportTarget{SequenceID: 55},
+		Workers:    1,
+		Checkpoint: checkpoint,
+	}
+	input := "email_address\njon@example.com\narya@example.com\nsansa@example.com\n"
+	sum, err := im.Import(ctx, strings.NewReader(input))
+	if err != context.Canceled {
+		t.Fatalf("Import() err = %v; want %v", err, context.Canceled)
+	}
+	if calls != 1 || sum.Imported != 1 {
+		t.Errorf("calls = %d, Imported = %d; want 1 and 1", calls, sum.Imported)
+	}
+	b, err := ioutil.ReadFile(checkpoint)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v; want nil", err)
+	}
+	if string(b) != `{"row":1}` {
+		t.Errorf("checkpoint = %s; want %s", b, `{"row":1}`)
+	}
+}
+
+func TestImporter_Import_invalid(t *testing.T) {
+	tests := map[string]struct {
+		importer convertkit.Importer
+		input    string
+		want     string
+	}{
+		"no target": {
+			importer: convertkit.Importer{},
+			input:    "email_address\n",
+			want:     "exactly one of FormID, SequenceID or TagID is required",
+		},
+		"missing column": {
+			importer: convertkit.Importer{
+				Target:  convertkit.ImportTarget{FormID: 213},
+				Mapping: convertkit.ColumnMapping{FirstName: "Name"},
+			},
+			input: "email_address\n",
+			want:  `missing column "Name"`,
+		},
+		"unknown format": {
+			importer: convertkit.Importer{Target: convertkit.ImportTarget{FormID: 213}, Format: "xml"},
+			want:     `unknown import format "xml"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.importer.Client = &convertkittest.Mock{}
+			_, err := tc.importer.Import(context.Background(), strings.NewReader(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Import() err = %v; want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package atomicfile writes files so that readers never see them partially
+// written.
+package atomicfile
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Write calls write with a temp file in the same directory as path, then
+// renames the temp file to path. If write or anything else fails, the temp
+// file is removed and any existing file at path is left untouched, so a crash
+// never leaves a partially written file behind.
+func Write(path string, write func(io.Writer) error) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	err = write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,52 @@
+package atomicfile_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joncalhoun/convertkit/internal/atomicfile"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+	err := atomicfile.Write(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "first")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Write() err = %v; want nil", err)
+	}
+
+	t.Run("failed writes leave the file alone", func(t *testing.T) {
+		wantErr := errors.New("boom")
+		err := atomicfile.Write(path, func(w io.Writer) error {
+			io.WriteString(w, "partial")
+			return wantErr
+		})
+		if err != wantErr {
+			t.Fatalf("Write() err = %v; want %v", err, wantErr)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile() err = %v; want nil", err)
+		}
+		if string(b) != "first" {
+			t.Errorf("contents = %q; want %q", b, "first")
+		}
+	})
+
+	t.Run("no temp files are left behind", func(t *testing.T) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("ReadDir() err = %v; want nil", err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("len(entries) = %d; want 1", len(entries))
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package convertkit
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DoPages calls Do for every page of a paginated endpoint, like Subscribers or
+// FormSubscriptions, and calls fn with the raw JSON of each page. This is
+// mostly useful for endpoints this package doesn't support yet.
+//
+// Paging starts with params["page"], or the first page if it isn't set. It
+// stops after the last page reported by total_pages, when fn returns an error,
+// or after a single call if the response doesn't have page and total_pages
+// fields. params is not modified.
+func (c *Client) DoPages(method, path string, params map[string]interface{}, fn func(page json.RawMessage) error) error {
+	p := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	want := 0
+	for {
+		var raw json.RawMessage
+		err := c.Do(method, path, p, &raw)
+		if err != nil {
+			return err
+		}
+		err = fn(raw)
+		if err != nil {
+			return err
+		}
+		var info struct {
+			Page       *int `json:"page"`
+			TotalPages *int `json:"total_pages"`
+		}
+		if json.Unmarshal(raw, &info) != nil || info.Page == nil || info.TotalPages == nil {
+			return nil
+		}
+		if want != 0 && *info.Page != want {
+			// Without this check an endpoint that ignores the page param would
+			// loop forever.
+			return fmt.Errorf("paginating: requested page %d, got page %d", want, *info.Page)
+		}
+		if *info.Page >= *info.TotalPages {
+			return nil
+		}
+		want = *info.Page + 1
+		p["page"] = want
+	}
+}
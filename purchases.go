@@ -0,0 +1,32 @@
+package convertkit
+
+import "time"
+
+// Purchase is a purchase made by a subscriber. Purchases are currently only
+// returned from webhooks.
+type Purchase struct {
+	RawJSON         `json:"-"`
+	ID              int               `json:"id"`
+	TransactionID   string            `json:"transaction_id"`
+	Status          string            `json:"status"`
+	Email           string            `json:"email_address"`
+	Currency        string            `json:"currency"`
+	TransactionTime time.Time         `json:"transaction_time"`
+	Subtotal        float64           `json:"subtotal"`
+	Shipping        float64           `json:"shipping"`
+	Discount        float64           `json:"discount"`
+	Tax             float64           `json:"tax"`
+	Total           float64           `json:"total"`
+	Products        []PurchaseProduct `json:"products"`
+}
+
+// PurchaseProduct is a single line item of a Purchase.
+type PurchaseProduct struct {
+	Raw       *RawJSON `json:"-"`
+	UnitPrice float64  `json:"unit_price"`
+	Quantity  int      `json:"quantity"`
+	SKU       string   `json:"sku"`
+	PID       int      `json:"pid"`
+	LID       int      `json:"lid"`
+	Name      string   `json:"name"`
+}
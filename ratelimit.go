@@ -0,0 +1,68 @@
+package convertkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the number of requests Convert Kit allows per API secret
+// in any 60 second period.
+const DefaultRateLimit = 120
+
+// RateLimiter limits the number of requests started within a rolling window,
+// which is how Convert Kit enforces its rate limit. It is safe for concurrent
+// use.
+type RateLimiter struct {
+	limit int
+	per   time.Duration
+
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that allows limit requests per period.
+func NewRateLimiter(limit int, per time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit: limit,
+		per:   per,
+	}
+}
+
+// RateLimiter returns the RateLimiter shared by everything using the client
+// that waits on one by default, such as BulkTag and Importer. It allows
+// DefaultRateLimit requests per minute.
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.state().limiter
+}
+
+// Wait blocks until another request can be started without going over the
+// limit, or until ctx is done. It returns ctx.Err() without waiting if ctx is
+// already done. A nil RateLimiter never blocks.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if rl == nil || ctx.Err() != nil {
+		return ctx.Err()
+	}
+	for {
+		rl.mu.Lock()
+		now := time.Now()
+		for len(rl.starts) > 0 && !rl.starts[0].Add(rl.per).After(now) {
+			rl.starts = rl.starts[1:]
+		}
+		if len(rl.starts) < rl.limit {
+			rl.starts = append(rl.starts, now)
+			rl.mu.Unlock()
+			return nil
+		}
+		wait := rl.starts[0].Add(rl.per).Sub(now)
+		rl.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
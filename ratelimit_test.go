@@ -0,0 +1,62 @@
+package convertkit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	rl := convertkit.NewRateLimiter(3, 100*time.Millisecond)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err := rl.Wait(ctx)
+		if err != nil {
+			t.Fatalf("Wait() err = %v; want nil", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("first 3 Wait() calls took %v; want no delay", elapsed)
+	}
+	err := rl.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Wait() err = %v; want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("4th Wait() returned after %v; want at least 100ms", elapsed)
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		rl := convertkit.NewRateLimiter(1, time.Hour)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		rl.Wait(ctx)
+		err := rl.Wait(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Wait() err = %v; want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var rl *convertkit.RateLimiter
+		err := rl.Wait(context.Background())
+		if err != nil {
+			t.Errorf("Wait() err = %v; want nil", err)
+		}
+	})
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	c := &convertkit.Client{Secret: "fake-secret-key"}
+	if c.RateLimiter() != c.RateLimiter() {
+		t.Errorf("RateLimiter() returned different limiters for the same client")
+	}
+	other := &convertkit.Client{Secret: "fake-secret-key"}
+	if c.RateLimiter() == other.RateLimiter() {
+		t.Errorf("RateLimiter() returned the same limiter for different clients")
+	}
+}
@@ -0,0 +1,239 @@
+package convertkit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RawJSON is embedded in response types so that the original JSON, along with
+// any fields this package doesn't model yet, can be inspected. Convert Kit
+// sometimes adds fields before this library knows about them, and this makes it
+// possible to read them without forking the library.
+//
+// Embedding RawJSON makes a type incomparable, since it holds a map. Tag, Form,
+// Sequence, PurchaseProduct and WebhookRule are compared with == and used as
+// map keys, so they hold a *RawJSON in a Raw field instead, which is nil unless
+// raw JSON was captured. Every other type, including the *Response types,
+// Subscriber and Purchase, embeds RawJSON. When CaptureRawJSON is set, ==
+// compares the Raw pointers of those five types, so values decoded separately
+// are never equal; compare their IDs instead.
+//
+// RawJSON is only populated when Client.CaptureRawJSON is true.
+type RawJSON struct {
+	// Raw is the original JSON used to decode the value.
+	Raw json.RawMessage
+	// Unknown contains every field in Raw that wasn't decoded into a struct
+	// field, keyed by its JSON name.
+	Unknown map[string]json.RawMessage
+}
+
+func (r *RawJSON) rawJSON() *RawJSON {
+	return r
+}
+
+// rawJSONHolder is implemented by any type that embeds RawJSON.
+type rawJSONHolder interface {
+	rawJSON() *RawJSON
+}
+
+var rawJSONPtrType = reflect.TypeOf((*RawJSON)(nil))
+
+// rawJSONOf returns the RawJSON for the struct v, allocating it if v has a Raw
+// *RawJSON field, or nil if v has neither.
+func rawJSONOf(v reflect.Value) *RawJSON {
+	if !v.CanAddr() {
+		return nil
+	}
+	if holder, ok := v.Addr().Interface().(rawJSONHolder); ok {
+		return holder.rawJSON()
+	}
+	f := v.FieldByName("Raw")
+	if !f.IsValid() || f.Type() != rawJSONPtrType || !f.CanSet() {
+		return nil
+	}
+	if f.IsNil() {
+		f.Set(reflect.New(rawJSONPtrType.Elem()))
+	}
+	return f.Interface().(*RawJSON)
+}
+
+// jsonRemapper is implemented by types with a custom UnmarshalJSON method. It
+// maps the raw JSON onto the type's fields (keyed by their JSON names) so that
+// nested values can still be walked.
+type jsonRemapper interface {
+	remapJSON(raw json.RawMessage) map[string]json.RawMessage
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// captureRawJSON walks v alongside the JSON it was decoded from and fills in
+// every RawJSON it finds.
+func captureRawJSON(raw []byte, v interface{}) {
+	var w jsonWalker
+	w.onObject = func(path string, raw json.RawMessage, obj map[string]json.RawMessage, unknown []string, v reflect.Value) {
+		rj := rawJSONOf(v)
+		if rj == nil {
+			return
+		}
+		rj.Raw = append(json.RawMessage(nil), raw...)
+		rj.Unknown = nil
+		for _, key := range unknown {
+			if rj.Unknown == nil {
+				rj.Unknown = make(map[string]json.RawMessage)
+			}
+			rj.Unknown[key] = obj[key]
+		}
+	}
+	w.walk("", raw, reflect.ValueOf(v))
+}
+
+// jsonWalker walks a decoded Go value alongside the JSON it was decoded from,
+// calling its callbacks as it goes.
+type jsonWalker struct {
+	// onObject is called for every struct that was decoded from a JSON object.
+	// unknown lists the keys in obj that don't map to a struct field.
+	onObject func(path string, raw json.RawMessage, obj map[string]json.RawMessage, unknown []string, v reflect.Value)
+	// onField is called for every struct field that has a matching JSON key,
+	// before the field's value is walked.
+	onField func(path string, raw json.RawMessage, f jsonField, v reflect.Value)
+	// onMissing is called for every struct field without a matching JSON key.
+	onMissing func(path string, f jsonField)
+}
+
+func (w *jsonWalker) walk(path string, raw json.RawMessage, v reflect.Value) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		w.walkStruct(path, raw, v)
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(raw, &elems) != nil {
+			return
+		}
+		for i := 0; i < len(elems) && i < v.Len(); i++ {
+			w.walk(joinPath(path, "["+strconv.Itoa(i)+"]"), elems[i], v.Index(i))
+		}
+	}
+}
+
+func (w *jsonWalker) walkStruct(path string, raw json.RawMessage, v reflect.Value) {
+	var obj map[string]json.RawMessage
+	var unknown []string
+	remapper, remap := addrInterface(v).(jsonRemapper)
+	switch {
+	case remap:
+		obj = remapper.remapJSON(raw)
+	case reflect.PtrTo(v.Type()).Implements(jsonUnmarshalerType):
+		// Types like time.Time decode themselves and have nothing to walk.
+		return
+	default:
+		if json.Unmarshal(raw, &obj) != nil || obj == nil {
+			return
+		}
+	}
+	fields := jsonFields(v.Type())
+	matched := make(map[string]bool, len(obj))
+	for _, f := range fields {
+		fieldPath := joinPath(path, f.name)
+		key, ok := matchKey(obj, f.name)
+		if !ok {
+			if w.onMissing != nil && !remap {
+				w.onMissing(fieldPath, f)
+			}
+			continue
+		}
+		matched[key] = true
+		fv := v.FieldByIndex(f.index)
+		if w.onField != nil {
+			w.onField(fieldPath, obj[key], f, fv)
+		}
+		w.walk(fieldPath, obj[key], fv)
+	}
+	if !remap {
+		for key := range obj {
+			if !matched[key] {
+				unknown = append(unknown, key)
+			}
+		}
+	}
+	if w.onObject != nil {
+		w.onObject(path, raw, obj, unknown, v)
+	}
+}
+
+// jsonField is a struct field along with the name encoding/json uses for it.
+type jsonField struct {
+	name  string
+	index []int
+	field reflect.StructField
+}
+
+// jsonFields returns the fields of t that encoding/json would decode into,
+// including fields promoted from untagged embedded structs.
+func jsonFields(t reflect.Type) []jsonField {
+	var ret []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, f := range jsonFields(ft) {
+					f.index = append([]int{i}, f.index...)
+					ret = append(ret, f)
+				}
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		ret = append(ret, jsonField{name: name, index: []int{i}, field: sf})
+	}
+	return ret
+}
+
+// matchKey finds the key in obj that encoding/json would use for name,
+// preferring an exact match over a case-insensitive one.
+func matchKey(obj map[string]json.RawMessage, name string) (string, bool) {
+	if _, ok := obj[name]; ok {
+		return name, true
+	}
+	for key := range obj {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func addrInterface(v reflect.Value) interface{} {
+	if !v.CanAddr() {
+		return nil
+	}
+	return v.Addr().Interface()
+}
+
+func joinPath(path, elem string) string {
+	if path == "" || strings.HasPrefix(elem, "[") {
+		return path + elem
+	}
+	return path + "." + elem
+}
@@ -0,0 +1,96 @@
+package convertkit_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func TestClient_CaptureRawJSON(t *testing.T) {
+	c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+  "total_subscribers": 1,
+  "page": 1,
+  "total_pages": 1,
+  "next_cursor": "abc",
+  "subscribers": [{
+    "id": 1,
+    "first_name": "Jon",
+    "email_address": "jonsnow@example.com",
+    "state": "active",
+    "created_at": "2016-02-28T08:07:00Z",
+    "fields": {"last_name": "Snow"},
+    "bounced": true
+  }]
+}`)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		resp, err := c.Subscribers(convertkit.SubscribersRequest{})
+		if err != nil {
+			t.Fatalf("Subscribers() err = %v; want nil", err)
+		}
+		if resp.Raw != nil || resp.Unknown != nil {
+			t.Errorf("RawJSON = %+v; want zero value", resp.RawJSON)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		c.CaptureRawJSON = true
+		resp, err := c.Subscribers(convertkit.SubscribersRequest{})
+		if err != nil {
+			t.Fatalf("Subscribers() err = %v; want nil", err)
+		}
+		if len(resp.Raw) == 0 {
+			t.Errorf("Raw is empty")
+		}
+		if got := string(resp.Unknown["next_cursor"]); got != `"abc"` {
+			t.Errorf("Unknown[next_cursor] = %v; want %v", got, `"abc"`)
+		}
+		if len(resp.Unknown) != 1 {
+			t.Errorf("len(Unknown) = %d; want 1", len(resp.Unknown))
+		}
+		sub := resp.Subscribers[0]
+		if got := string(sub.Unknown["bounced"]); got != "true" {
+			t.Errorf("Subscriber.Unknown[bounced] = %v; want true", got)
+		}
+		if len(sub.Unknown) != 1 {
+			t.Errorf("len(Subscriber.Unknown) = %d; want 1", len(sub.Unknown))
+		}
+	})
+}
+
+func TestClient_CaptureRawJSON_customDecoders(t *testing.T) {
+	c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "name": "House Stark", "created_at": "2016-02-28T08:07:00Z", "color": "grey"}`)
+	})
+	c.CaptureRawJSON = true
+	resp, err := c.CreateTags("House Stark")
+	if err != nil {
+		t.Fatalf("CreateTags() err = %v; want nil", err)
+	}
+	if len(resp.Raw) == 0 {
+		t.Errorf("Raw is empty")
+	}
+	if len(resp.Unknown) != 0 {
+		t.Errorf("Unknown = %v; want empty", resp.Unknown)
+	}
+	if resp.Tags[0].Raw == nil {
+		t.Fatalf("Tags[0].Raw = nil; want it to be set")
+	}
+	if got := string(resp.Tags[0].Raw.Unknown["color"]); got != `"grey"` {
+		t.Errorf("Tags[0].Raw.Unknown[color] = %v; want %v", got, `"grey"`)
+	}
+}
+
+func TestEntitiesAreComparable(t *testing.T) {
+	// These are compared with == and used as map keys, so capturing raw JSON
+	// mustn't make them incomparable.
+	_ = map[convertkit.Tag]bool{{ID: 1}: true}
+	_ = map[convertkit.Form]bool{{ID: 1}: true}
+	_ = map[convertkit.Sequence]bool{{ID: 1}: true}
+	_ = map[convertkit.PurchaseProduct]bool{{PID: 1}: true}
+	_ = map[convertkit.WebhookRule]bool{{ID: 1}: true}
+}
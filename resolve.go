@@ -0,0 +1,282 @@
+package convertkit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NameAPI is the set of Client methods used by a Resolver.
+type NameAPI interface {
+	Tags() (*TagsResponse, error)
+	Forms() (*FormsResponse, error)
+	Sequences() (*SequencesResponse, error)
+}
+
+// Errors matched by NameError. Use errors.Is to check for them.
+var (
+	ErrUnknownName   = errors.New("unknown name")
+	ErrAmbiguousName = errors.New("ambiguous name")
+)
+
+// NameError is returned when a name doesn't match exactly one tag, form or
+// sequence.
+type NameError struct {
+	// Kind is "tag", "form" or "sequence".
+	Kind string
+	Name string
+	// IDs lists every match when the name is ambiguous.
+	IDs []int
+}
+
+func (e NameError) Error() string {
+	if len(e.IDs) == 0 {
+		return fmt.Sprintf("unknown %s %q", e.Kind, e.Name)
+	}
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fmt.Sprintf("ambiguous %s %q matches IDs %s", e.Kind, e.Name, strings.Join(ids, ", "))
+}
+
+// Is reports whether target is ErrUnknownName or ErrAmbiguousName, whichever
+// applies.
+func (e NameError) Is(target error) bool {
+	if len(e.IDs) == 0 {
+		return target == ErrUnknownName
+	}
+	return target == ErrAmbiguousName
+}
+
+// Resolver maps tag, form and sequence names to IDs. Names are listed the
+// first time they are needed and cached, and the cache is refreshed whenever a
+// name isn't found in it, so newly created resources can be used right away.
+//
+// Names are matched case-insensitively, ignoring leading and trailing spaces.
+// When several resources match, a single exact match is used; otherwise the
+// name is ambiguous. It is safe for concurrent use.
+type Resolver struct {
+	Client NameAPI
+
+	mu    sync.Mutex
+	cache *nameCache
+}
+
+// Resolver returns a Resolver for the client. Resolvers returned by the same
+// Client share a cache.
+func (c *Client) Resolver() *Resolver {
+	return &Resolver{
+		Client: c,
+		cache:  &c.state().names,
+	}
+}
+
+// TagID returns the ID of the tag with the given name.
+func (r *Resolver) TagID(name string) (TagID, error) {
+	id, err := r.resolve(kindTag, name)
+	return TagID(id), err
+}
+
+// FormID returns the ID of the form with the given name.
+func (r *Resolver) FormID(name string) (FormID, error) {
+	id, err := r.resolve(kindForm, name)
+	return FormID(id), err
+}
+
+// SequenceID returns the ID of the sequence with the given name.
+func (r *Resolver) SequenceID(name string) (SequenceID, error) {
+	id, err := r.resolve(kindSequence, name)
+	return SequenceID(id), err
+}
+
+// Refresh clears the cache so that every name is listed again on next use.
+func (r *Resolver) Refresh() {
+	c := r.names()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+const (
+	kindTag      = "tag"
+	kindForm     = "form"
+	kindSequence = "sequence"
+)
+
+// nameCache holds the names of each kind of resource, keyed by nameKey.
+type nameCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string][]nameEntry
+}
+
+type nameEntry struct {
+	id   int
+	name string
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func (r *Resolver) names() *nameCache {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cache == nil {
+		r.cache = &nameCache{}
+	}
+	return r.cache
+}
+
+// forget removes a kind from the cache, eg after an ID it returned no longer
+// exists.
+func (r *Resolver) forget(kind string) {
+	c := r.names()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, kind)
+}
+
+func (r *Resolver) resolve(kind, name string) (int, error) {
+	c := r.names()
+	key := nameKey(name)
+	c.mu.Lock()
+	matches, cached := c.entries[kind][key]
+	c.mu.Unlock()
+	if !cached {
+		// List outside the lock so that lookups of other kinds, and of names
+		// that are already cached, aren't held up by the request.
+		entries, err := r.list(kind)
+		if err != nil {
+			return 0, err
+		}
+		c.mu.Lock()
+		if c.entries == nil {
+			c.entries = make(map[string]map[string][]nameEntry)
+		}
+		c.entries[kind] = entries
+		c.mu.Unlock()
+		matches = entries[key]
+	}
+	switch len(matches) {
+	case 0:
+		return 0, NameError{Kind: kind, Name: name}
+	case 1:
+		return matches[0].id, nil
+	}
+	var exact []nameEntry
+	for _, m := range matches {
+		if m.name == strings.TrimSpace(name) {
+			exact = append(exact, m)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0].id, nil
+	}
+	ids := make([]int, len(matches))
+	for i, m := range matches {
+		ids[i] = m.id
+	}
+	sort.Ints(ids)
+	return 0, NameError{Kind: kind, Name: name, IDs: ids}
+}
+
+// list fetches every name of the given kind.
+func (r *Resolver) list(kind string) (map[string][]nameEntry, error) {
+	entries := make(map[string][]nameEntry)
+	add := func(id int, name string) {
+		key := nameKey(name)
+		entries[key] = append(entries[key], nameEntry{id: id, name: strings.TrimSpace(name)})
+	}
+	switch kind {
+	case kindTag:
+		resp, err := r.Client.Tags()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Tags {
+			add(int(t.ID), t.Name)
+		}
+	case kindForm:
+		resp, err := r.Client.Forms()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Forms {
+			add(int(f.ID), f.Name)
+		}
+	case kindSequence:
+		resp, err := r.Client.Sequences()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Sequences {
+			add(int(s.ID), s.Name)
+		}
+	}
+	return entries, nil
+}
+
+// TagSubscriberByName is TagSubscriber using the tag's name instead of
+// req.TagID.
+func (c *Client) TagSubscriberByName(tag string, req TagSubscriberRequest) (*TagSubscriberResponse, error) {
+	r := c.Resolver()
+	id, err := r.TagID(tag)
+	if err != nil {
+		return nil, err
+	}
+	req.TagID = id
+	resp, err := c.TagSubscriber(req)
+	return resp, r.check(kindTag, err)
+}
+
+// UntagSubscriberByName is UntagSubscriber using the tag's name instead of
+// req.TagID.
+func (c *Client) UntagSubscriberByName(tag string, req UntagSubscriberRequest) (*UntagSubscriberResponse, error) {
+	r := c.Resolver()
+	id, err := r.TagID(tag)
+	if err != nil {
+		return nil, err
+	}
+	req.TagID = id
+	resp, err := c.UntagSubscriber(req)
+	return resp, r.check(kindTag, err)
+}
+
+// SubscribeToFormByName is SubscribeToForm using the form's name instead of
+// req.FormID.
+func (c *Client) SubscribeToFormByName(form string, req SubscribeToFormRequest) (*SubscribeToFormResponse, error) {
+	r := c.Resolver()
+	id, err := r.FormID(form)
+	if err != nil {
+		return nil, err
+	}
+	req.FormID = id
+	resp, err := c.SubscribeToForm(req)
+	return resp, r.check(kindForm, err)
+}
+
+// SubscribeToSequenceByName is SubscribeToSequence using the sequence's name
+// instead of req.SequenceID.
+func (c *Client) SubscribeToSequenceByName(sequence string, req SubscribeToSequenceRequest) (*SubscribeToSequenceResponse, error) {
+	r := c.Resolver()
+	id, err := r.SequenceID(sequence)
+	if err != nil {
+		return nil, err
+	}
+	req.SequenceID = id
+	resp, err := c.SubscribeToSequence(req)
+	return resp, r.check(kindSequence, err)
+}
+
+// check forgets the cached names of a kind when a call using one of its IDs
+// returns a 404, since the resource was probably deleted or renamed.
+func (r *Resolver) check(kind string, err error) error {
+	var ckErr ErrorResponse
+	if errors.As(err, &ckErr) && ckErr.StatusCode == 404 {
+		r.forget(kind)
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package convertkit_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
+)
+
+func resolveServer(t *testing.T) (*convertkittest.Server, *convertkit.Client, *countingClient) {
+	t.Helper()
+	srv := convertkittest.NewServer("fake-secret-key")
+	t.Cleanup(srv.Close)
+	srv.Seed(convertkittest.Fixtures{
+		Forms:     []convertkit.Form{{ID: 213, Name: "Newsletter"}},
+		Sequences: []convertkit.Sequence{{ID: 55, Name: "Onboarding"}},
+		Tags: []convertkit.Tag{
+			{ID: 14, Name: "Customer"},
+			{ID: 16, Name: "Dupe"},
+			{ID: 17, Name: "dupe"},
+		},
+	})
+	c := srv.Client()
+	cc := &countingClient{HTTPClient: c.HTTPClient}
+	c.HTTPClient = cc
+	return srv, c, cc
+}
+
+func TestResolver(t *testing.T) {
+	srv, c, cc := resolveServer(t)
+	r := c.Resolver()
+	tests := map[string]struct {
+		resolve func() (int, error)
+		want    int
+		err     error
+		msg     string
+	}{
+		"tag": {
+			resolve: func() (int, error) { id, err := r.TagID(" customer "); return int(id), err },
+			want:    14,
+		},
+		"exact match": {
+			resolve: func() (int, error) { id, err := r.TagID("dupe"); return int(id), err },
+			want:    17,
+		},
+		"ambiguous": {
+			resolve: func() (int, error) { id, err := r.TagID("DUPE"); return int(id), err },
+			err:     convertkit.ErrAmbiguousName,
+			msg:     `ambiguous tag "DUPE" matches IDs 16, 17`,
+		},
+		"unknown": {
+			resolve: func() (int, error) { id, err := r.TagID("Nope"); return int(id), err },
+			err:     convertkit.ErrUnknownName,
+			msg:     `unknown tag "Nope"`,
+		},
+		"form": {
+			resolve: func() (int, error) { id, err := r.FormID("newsletter"); return int(id), err },
+			want:    213,
+		},
+		"sequence": {
+			resolve: func() (int, error) { id, err := r.SequenceID("Onboarding"); return int(id), err },
+			want:    55,
+		},
+		"unknown sequence": {
+			resolve: func() (int, error) { id, err := r.SequenceID("Offboarding"); return int(id), err },
+			err:     convertkit.ErrUnknownName,
+			msg:     `unknown sequence "Offboarding"`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.resolve()
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v; want %v", err, tc.err)
+			}
+			if err != nil && err.Error() != tc.msg {
+				t.Errorf("err = %q; want %q", err, tc.msg)
+			}
+			if got != tc.want {
+				t.Errorf("ID = %d; want %d", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		cc.counts = nil
+		// A new Resolver from the same Client shares the cache.
+		id, err := c.Resolver().TagID("Customer")
+		if err != nil || id != 14 {
+			t.Fatalf("TagID() = %v, %v; want 14, nil", id, err)
+		}
+		if cc.counts[http.MethodGet] != 0 {
+			t.Errorf("GET requests = %d; want 0", cc.counts[http.MethodGet])
+		}
+	})
+
+	t.Run("separate clients", func(t *testing.T) {
+		cc.counts = nil
+		other := srv.Client()
+		other.HTTPClient = cc
+		_, err := other.Resolver().TagID("Customer")
+		if err != nil {
+			t.Fatalf("TagID() err = %v; want nil", err)
+		}
+		if cc.counts[http.MethodGet] != 1 {
+			t.Errorf("GET requests = %d; want 1", cc.counts[http.MethodGet])
+		}
+	})
+
+	t.Run("refresh on miss", func(t *testing.T) {
+		resp, err := srv.Client().CreateTags("Launch")
+		if err != nil {
+			t.Fatalf("CreateTags() err = %v; want nil", err)
+		}
+		id, err := r.TagID("Launch")
+		if err != nil || id != resp.Tags[0].ID {
+			t.Errorf("TagID() = %v, %v; want %v, nil", id, err, resp.Tags[0].ID)
+		}
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		r.Refresh()
+		cc.counts = nil
+		_, err := r.FormID("Newsletter")
+		if err != nil {
+			t.Fatalf("FormID() err = %v; want nil", err)
+		}
+		if cc.counts[http.MethodGet] != 1 {
+			t.Errorf("GET requests = %d; want 1", cc.counts[http.MethodGet])
+		}
+	})
+}
+
+func TestClient_ByName(t *testing.T) {
+	srv, c, _ := resolveServer(t)
+	_, err := c.SubscribeToFormByName("Newsletter", convertkit.SubscribeToFormRequest{Email: "jon@example.com"})
+	if err != nil {
+		t.Fatalf("SubscribeToFormByName() err = %v; want nil", err)
+	}
+	_, err = c.SubscribeToSequenceByName("onboarding", convertkit.SubscribeToSequenceRequest{Email: "jon@example.com"})
+	if err != nil {
+		t.Fatalf("SubscribeToSequenceByName() err = %v; want nil", err)
+	}
+	resp, err := c.TagSubscriberByName("Customer", convertkit.TagSubscriberRequest{Email: "jon@example.com"})
+	if err != nil {
+		t.Fatalf("TagSubscriberByName() err = %v; want nil", err)
+	}
+	subID := resp.Subscription.Subscriber.ID
+	if got := fmt.Sprint(srv.SubscriberTags(subID)); got != "[14]" {
+		t.Errorf("SubscriberTags() = %v; want [14]", got)
+	}
+	_, err = c.UntagSubscriberByName("Customer", convertkit.UntagSubscriberRequest{SubscriberID: subID})
+	if err != nil {
+		t.Fatalf("UntagSubscriberByName() err = %v; want nil", err)
+	}
+	if got := srv.SubscriberTags(subID); len(got) != 0 {
+		t.Errorf("SubscriberTags() = %v; want none", got)
+	}
+	_, err = c.TagSubscriberByName("DUPE", convertkit.TagSubscriberRequest{Email: "jon@example.com"})
+	if !errors.Is(err, convertkit.ErrAmbiguousName) {
+		t.Errorf("TagSubscriberByName() err = %v; want %v", err, convertkit.ErrAmbiguousName)
+	}
+}
+
+func TestClient_ByName_notFound(t *testing.T) {
+	lists := 0
+	c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /tags":
+			lists++
+			fmt.Fprint(w, `{"tags": [{"id": 14, "name": "Customer"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "Not Found", "message": "The entity you were trying to find doesn't exist"}`)
+		}
+	})
+	for i := 0; i < 2; i++ {
+		_, err := c.TagSubscriberByName("Customer", convertkit.TagSubscriberRequest{Email: "jon@example.com"})
+		var ckErr convertkit.ErrorResponse
+		if !errors.As(err, &ckErr) || ckErr.StatusCode != http.StatusNotFound {
+			t.Fatalf("TagSubscriberByName() err = %v; want a 404", err)
+		}
+	}
+	// The tag was probably deleted or renamed, so names are listed again
+	// after a 404.
+	if lists != 2 {
+		t.Errorf("tag lists = %d; want 2", lists)
+	}
+}
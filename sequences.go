@@ -8,15 +8,17 @@ import (
 // Sequence is a series of emails that a user might receive. It was previously
 // called a course, which is why some the JSON is a little wonky.
 type Sequence struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	Raw       *RawJSON   `json:"-"`
+	ID        SequenceID `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 // SequencesResponse is returned after making a Sequence call.
 //
 // Sequences were previously called courses, hence the mismatched json naming.
 type SequencesResponse struct {
+	RawJSON   `json:"-"`
 	Sequences []Sequence `json:"courses"`
 }
 
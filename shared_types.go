@@ -39,23 +39,26 @@ func NewDate(year, month, day int) Date {
 type Date time.Time
 
 // MarshalJSON converts a Date into yyyy-mm-dd format
-func (d *Date) MarshalJSON() ([]byte, error) {
-	if d == nil {
-		return nil, nil
-	}
-	t := time.Time(*d)
+func (d Date) MarshalJSON() ([]byte, error) {
+	t := time.Time(d)
 	year, month, day := t.Date()
-	return json.Marshal(fmt.Sprintf("%4d-%02d-%02d", year, month, day))
+	return json.Marshal(fmt.Sprintf("%04d-%02d-%02d", year, month, day))
 }
 
-// TODO: Test this, assuming we even need it. Not so sure we do at all.
-// // UnmarhsalJSON parses yyyy-mm-dd format into a Date
-// func (d *Date) UnmarhsalJSON(b []byte) error {
-// 	if len(b) == 0 {
-// 		return nil
-// 	}
-// 	var year, month, day int
-// 	fmt.Fscanf(bytes.NewReader(b), "\"%4d-%2d-%2d\"", &year, &month, &day)
-// 	*d = Date(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC))
-// 	return nil
-// }
+// UnmarshalJSON parses yyyy-mm-dd format into a Date
+func (d *Date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return fmt.Errorf("date: %w", err)
+	}
+	*d = Date(t)
+	return nil
+}
@@ -0,0 +1,168 @@
+package convertkit
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// SubscriberChange is a single field that differs between two subscribers.
+// Field uses the JSON name of the field, and custom fields are prefixed with
+// "fields." (eg "fields.last_name").
+type SubscriberChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+func (sc SubscriberChange) String() string {
+	return fmt.Sprintf("%s: %q -> %q", sc.Field, sc.From, sc.To)
+}
+
+// DiffSubscriber compares the current state of a subscriber with the desired
+// state and returns the changes along with the minimal UpdateSubscriberRequest
+// needed to apply them. If nothing changed the request will be nil.
+//
+// Only fields that can be updated via the API are compared. Empty FirstName and
+// Email values in desired are treated as "leave unchanged" since the API
+// doesn't support clearing them. Custom fields are only compared for keys
+// present in desired.Fields, so an empty string value can be used to clear a
+// custom field. Emails are compared case-insensitively.
+func DiffSubscriber(current, desired Subscriber) ([]SubscriberChange, *UpdateSubscriberRequest) {
+	var changes []SubscriberChange
+	req := UpdateSubscriberRequest{
+		SubscriberID: current.ID,
+	}
+	if desired.FirstName != "" && desired.FirstName != current.FirstName {
+		changes = append(changes, SubscriberChange{
+			Field: "first_name",
+			From:  current.FirstName,
+			To:    desired.FirstName,
+		})
+		req.FirstName = desired.FirstName
+	}
+	if desired.Email != "" && !strings.EqualFold(desired.Email, current.Email) {
+		changes = append(changes, SubscriberChange{
+			Field: "email_address",
+			From:  current.Email,
+			To:    desired.Email,
+		})
+		req.Email = desired.Email
+	}
+	keys := make([]string, 0, len(desired.Fields))
+	for k := range desired.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		from, to := current.Fields[k], desired.Fields[k]
+		if from == to {
+			continue
+		}
+		changes = append(changes, SubscriberChange{
+			Field: "fields." + k,
+			From:  from,
+			To:    to,
+		})
+		if req.Fields == nil {
+			req.Fields = make(map[string]string)
+		}
+		req.Fields[k] = to
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return changes, &req
+}
+
+// UpsertSubscriberRequest is used when making UpsertSubscriber calls.
+type UpsertSubscriberRequest struct {
+	// Required
+	Email string
+	// Optional
+	FirstName string
+	Fields    map[string]string
+	// FormID is the form used to create the subscriber if one doesn't exist.
+	// If it is zero and no subscriber exists, UpsertSubscriber returns a 404
+	// ErrorResponse.
+	FormID FormID
+}
+
+// Validate checks the request for missing or malformed fields.
+func (r UpsertSubscriberRequest) Validate() error {
+	var v validator
+	v.requireEmail("Email", r.Email)
+	return v.err()
+}
+
+// UpsertSubscriberResponse is the response data from UpsertSubscriber.
+type UpsertSubscriberResponse struct {
+	// Subscriber is the subscriber after any changes were applied.
+	Subscriber Subscriber
+	// Changes lists the fields that were updated. It is empty if the subscriber
+	// was already up to date or was just created.
+	Changes []SubscriberChange
+	Created bool
+	Updated bool
+}
+
+// UpsertSubscriber looks up a subscriber by email and only updates them if
+// something actually changed. This avoids using up rate limits and changing
+// the subscriber's updated_at time needlessly. If the subscriber doesn't exist
+// and a FormID is provided they are subscribed to that form.
+func (c *Client) UpsertSubscriber(req UpsertSubscriberRequest) (*UpsertSubscriberResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	subs, err := c.Subscribers(SubscribersRequest{
+		Email: req.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	desired := Subscriber{
+		FirstName: req.FirstName,
+		Email:     req.Email,
+		Fields:    req.Fields,
+	}
+	for _, current := range subs.Subscribers {
+		if !strings.EqualFold(current.Email, req.Email) {
+			continue
+		}
+		changes, update := DiffSubscriber(current, desired)
+		if update == nil {
+			return &UpsertSubscriberResponse{Subscriber: current}, nil
+		}
+		resp, err := c.UpdateSubscriber(*update)
+		if err != nil {
+			return nil, err
+		}
+		return &UpsertSubscriberResponse{
+			Subscriber: resp.Subscriber,
+			Changes:    changes,
+			Updated:    true,
+		}, nil
+	}
+	if req.FormID == 0 {
+		return nil, ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Type:       "not_found_error",
+			Message:    fmt.Sprintf("subscriber not found: %v", req.Email),
+		}
+	}
+	resp, err := c.SubscribeToForm(SubscribeToFormRequest{
+		FormID:    req.FormID,
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		Fields:    req.Fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UpsertSubscriberResponse{
+		Subscriber: resp.Subscription.Subscriber,
+		Created:    true,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package convertkit_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func TestDiffSubscriber(t *testing.T) {
+	current := convertkit.Subscriber{
+		ID:        1,
+		FirstName: "Jon",
+		Email:     "jonsnow@example.com",
+		Fields: map[string]string{
+			"last_name": "Snow",
+			"house":     "Stark",
+		},
+	}
+	for name, tc := range map[string]struct {
+		desired     convertkit.Subscriber
+		wantChanges []convertkit.SubscriberChange
+		wantReq     *convertkit.UpdateSubscriberRequest
+	}{
+		"no changes": {
+			desired: convertkit.Subscriber{
+				FirstName: "Jon",
+				Email:     "JonSnow@example.com",
+				Fields:    map[string]string{"last_name": "Snow"},
+			},
+		},
+		"empty values are ignored": {
+			desired: convertkit.Subscriber{},
+		},
+		"first name": {
+			desired: convertkit.Subscriber{FirstName: "Aegon"},
+			wantChanges: []convertkit.SubscriberChange{
+				{Field: "first_name", From: "Jon", To: "Aegon"},
+			},
+			wantReq: &convertkit.UpdateSubscriberRequest{
+				SubscriberID: 1,
+				FirstName:    "Aegon",
+			},
+		},
+		"email and fields": {
+			desired: convertkit.Subscriber{
+				Email: "aegon@example.com",
+				Fields: map[string]string{
+					"last_name": "Targaryen",
+					"house":     "Stark",
+					"title":     "King in the North",
+				},
+			},
+			wantChanges: []convertkit.SubscriberChange{
+				{Field: "email_address", From: "jonsnow@example.com", To: "aegon@example.com"},
+				{Field: "fields.last_name", From: "Snow", To: "Targaryen"},
+				{Field: "fields.title", From: "", To: "King in the North"},
+			},
+			wantReq: &convertkit.UpdateSubscriberRequest{
+				SubscriberID: 1,
+				Email:        "aegon@example.com",
+				Fields: map[string]string{
+					"last_name": "Targaryen",
+					"title":     "King in the North",
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			changes, req := convertkit.DiffSubscriber(current, tc.desired)
+			if !reflect.DeepEqual(changes, tc.wantChanges) {
+				t.Errorf("changes = %v; want %v", changes, tc.wantChanges)
+			}
+			if !reflect.DeepEqual(req, tc.wantReq) {
+				t.Errorf("req = %+v; want %+v", req, tc.wantReq)
+			}
+		})
+	}
+}
+
+func TestClient_UpsertSubscriber(t *testing.T) {
+	upsertClient := func(t *testing.T) (*convertkit.Client, *[]string) {
+		var calls []string
+		c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			switch r.Method + " " + r.URL.Path {
+			case "GET /subscribers":
+				r.ParseForm()
+				if r.FormValue("email_address") == "new@example.com" {
+					w.Write([]byte(`{"total_subscribers": 0, "page": 1, "total_pages": 1, "subscribers": []}`))
+					return
+				}
+				testdataHandler(t, "GET_subscribers")(w, r)
+			case "PUT /subscribers/1":
+				testdataHandler(t, "PUT_subscribers_123")(w, r)
+			case "POST /forms/213/subscribe":
+				testdataHandler(t, "POST_forms_213_subscribe")(w, r)
+			default:
+				t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		return c, &calls
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		c, calls := upsertClient(t)
+		resp, err := c.UpsertSubscriber(convertkit.UpsertSubscriberRequest{
+			Email:     "jonsnow@example.com",
+			FirstName: "Jon",
+		})
+		if err != nil {
+			t.Fatalf("UpsertSubscriber() err = %v; want nil", err)
+		}
+		if resp.Updated || resp.Created {
+			t.Errorf("Updated, Created = %v, %v; want false, false", resp.Updated, resp.Created)
+		}
+		if want := []string{"GET /subscribers"}; !reflect.DeepEqual(*calls, want) {
+			t.Errorf("calls = %v; want %v", *calls, want)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		c, calls := upsertClient(t)
+		resp, err := c.UpsertSubscriber(convertkit.UpsertSubscriberRequest{
+			Email:     "jonsnow@example.com",
+			FirstName: "Aegon",
+		})
+		if err != nil {
+			t.Fatalf("UpsertSubscriber() err = %v; want nil", err)
+		}
+		if !resp.Updated {
+			t.Errorf("Updated = false; want true")
+		}
+		if len(resp.Changes) != 1 {
+			t.Errorf("len(Changes) = %d; want 1", len(resp.Changes))
+		}
+		if want := []string{"GET /subscribers", "PUT /subscribers/1"}; !reflect.DeepEqual(*calls, want) {
+			t.Errorf("calls = %v; want %v", *calls, want)
+		}
+	})
+
+	t.Run("created", func(t *testing.T) {
+		c, _ := upsertClient(t)
+		resp, err := c.UpsertSubscriber(convertkit.UpsertSubscriberRequest{
+			Email:  "new@example.com",
+			FormID: 213,
+		})
+		if err != nil {
+			t.Fatalf("UpsertSubscriber() err = %v; want nil", err)
+		}
+		if !resp.Created {
+			t.Errorf("Created = false; want true")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c, _ := upsertClient(t)
+		_, err := c.UpsertSubscriber(convertkit.UpsertSubscriberRequest{
+			Email: "new@example.com",
+		})
+		var ckErr convertkit.ErrorResponse
+		if !errors.As(err, &ckErr) {
+			t.Fatalf("UpsertSubscriber() err type = %T; want %T", err, ckErr)
+		}
+		if ckErr.StatusCode != http.StatusNotFound {
+			t.Errorf("StatusCode = %v; want %v", ckErr.StatusCode, http.StatusNotFound)
+		}
+	})
+}
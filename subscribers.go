@@ -9,7 +9,8 @@ import (
 // Subscriber is a user subscribed to your mailing list. This type is returned
 // from several API endpoints.
 type Subscriber struct {
-	ID        int               `json:"id"`
+	RawJSON   `json:"-"`
+	ID        SubscriberID      `json:"id"`
 	FirstName string            `json:"first_name"`
 	Email     string            `json:"email_address"`
 	State     string            `json:"state"`
@@ -32,8 +33,18 @@ type SubscribersRequest struct {
 	Email       string    `json:"email_address,omitempty"`
 }
 
+// Validate checks the request for missing or malformed fields.
+func (r SubscribersRequest) Validate() error {
+	var v validator
+	v.nonNegative("Page", r.Page)
+	v.sortOrder("SortOrder", r.SortOrder)
+	v.email("Email", r.Email)
+	return v.err()
+}
+
 // SubscribersResponse is the data returned from a Subscribers call.
 type SubscribersResponse struct {
+	RawJSON          `json:"-"`
 	TotalSubscribers int          `json:"total_subscribers"`
 	Page             int          `json:"page"`
 	TotalPages       int          `json:"total_pages"`
@@ -50,18 +61,59 @@ func (c *Client) Subscribers(req SubscribersRequest) (*SubscribersResponse, erro
 	return &ret, nil
 }
 
+// SubscriberResponse is the data returned from a Subscriber call.
+type SubscriberResponse struct {
+	RawJSON    `json:"-"`
+	Subscriber `json:"subscriber"`
+}
+
+// Subscriber returns a single subscriber.
+func (c *Client) Subscriber(id SubscriberID) (*SubscriberResponse, error) {
+	var ret SubscriberResponse
+	err := c.Do(http.MethodGet, fmt.Sprintf("subscribers/%v", id), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// SubscriberTagsResponse is the data returned from a SubscriberTags call.
+type SubscriberTagsResponse struct {
+	RawJSON `json:"-"`
+	Tags    []Tag `json:"tags"`
+}
+
+// SubscriberTags lists the tags applied to a subscriber.
+func (c *Client) SubscriberTags(id SubscriberID) (*SubscriberTagsResponse, error) {
+	var ret SubscriberTagsResponse
+	err := c.Do(http.MethodGet, fmt.Sprintf("subscribers/%v/tags", id), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
 // UpdateSubscriberRequest is used to update a subscriber.
 type UpdateSubscriberRequest struct {
 	// Required
-	SubscriberID int `json:"-"`
+	SubscriberID SubscriberID `json:"-"`
 	// Optional
 	FirstName string            `json:"first_name,omitempty"`
 	Email     string            `json:"email_address,omitempty"`
 	Fields    map[string]string `json:"fields,omitempty"`
 }
 
+// Validate checks the request for missing or malformed fields.
+func (r UpdateSubscriberRequest) Validate() error {
+	var v validator
+	v.requireID("SubscriberID", int(r.SubscriberID))
+	v.email("Email", r.Email)
+	return v.err()
+}
+
 // UpdateSubscriberResponse is the data returned from a UpdateSubscriber call.
 type UpdateSubscriberResponse struct {
+	RawJSON    `json:"-"`
 	Subscriber `json:"subscriber"`
 }
 
@@ -75,17 +127,25 @@ func (c *Client) UpdateSubscriber(req UpdateSubscriberRequest) (*UpdateSubscribe
 	return &ret, nil
 }
 
+type unsubscribeSubscriberRequest struct {
+	Email string `json:"email"`
+}
+
+func (r unsubscribeSubscriberRequest) Validate() error {
+	var v validator
+	v.requireEmail("Email", r.Email)
+	return v.err()
+}
+
 // UnsubscribeSubscriberResponse is the data returned from an UnsubscribeSubscriber call.
 type UnsubscribeSubscriberResponse struct {
+	RawJSON    `json:"-"`
 	Subscriber `json:"subscriber"`
 }
 
 // UnsubscribeSubscriber will update a subscriber's information.
 func (c *Client) UnsubscribeSubscriber(email string) (*UnsubscribeSubscriberResponse, error) {
-	var req struct {
-		Email string `json:"email"`
-	}
-	req.Email = email
+	req := unsubscribeSubscriberRequest{Email: email}
 	var ret UnsubscribeSubscriberResponse
 	err := c.Do(http.MethodPut, "unsubscribe", req, &ret)
 	if err != nil {
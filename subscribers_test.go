@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
 )
 
 // This test is incredibly exhaustive because the requests here use a few custom
@@ -148,6 +149,34 @@ func TestClient_Subscribers(t *testing.T) {
 	})
 }
 
+func TestClient_Subscriber(t *testing.T) {
+	c := client(t, "fake-secret-key")
+	resp, err := c.Subscriber(123)
+	if err != nil {
+		t.Fatalf("Subscriber() err = %v; want %v", err, nil)
+	}
+	if resp.Email != "jonsnow@example.com" {
+		t.Errorf("Email = %v; want %v", resp.Email, "jonsnow@example.com")
+	}
+	if resp.Fields["last_name"] != "Snow" {
+		t.Errorf("Fields[last_name] = %v; want %v", resp.Fields["last_name"], "Snow")
+	}
+}
+
+func TestClient_SubscriberTags(t *testing.T) {
+	c := client(t, "fake-secret-key")
+	resp, err := c.SubscriberTags(123)
+	if err != nil {
+		t.Fatalf("SubscriberTags() err = %v; want %v", err, nil)
+	}
+	if len(resp.Tags) != 2 {
+		t.Fatalf("len(Tags) = %v; want %v", len(resp.Tags), 2)
+	}
+	if resp.Tags[1].Name != "House Lannister" {
+		t.Errorf("Tags[1].Name = %v; want %v", resp.Tags[1].Name, "House Lannister")
+	}
+}
+
 func TestClient_UpdateSubscriber(t *testing.T) {
 	c := client(t, "fake-secret-key")
 	t.Run("response data", func(t *testing.T) {
@@ -163,6 +192,19 @@ func TestClient_UpdateSubscriber(t *testing.T) {
 		}
 	})
 
+	t.Run("empty fields are omitted", func(t *testing.T) {
+		exp := convertkittest.Expect(t, "testdata", "PUT_subscribers_123")
+		c := clientWithHandler(t, exp.Handler(baseHandler(t, "fake-secret-key")).ServeHTTP)
+		c.Secret = "fake-secret-key"
+		_, err := c.UpdateSubscriber(convertkit.UpdateSubscriberRequest{
+			SubscriberID: 123,
+			FirstName:    "New First Name",
+		})
+		if err != nil {
+			t.Fatalf("UpdateSubscriber() err = %v; want %v", err, nil)
+		}
+	})
+
 	t.Run("options are sent to server", func(t *testing.T) {
 		for name, tc := range map[string]struct {
 			want map[string]interface{}
@@ -11,6 +11,7 @@ import (
 // entity being subscribed to something. Typically this is a Subscriber being
 // subscribed to a Sequence of Form.
 type Subscription struct {
+	RawJSON          `json:"-"`
 	ID               int         `json:"id"`
 	State            string      `json:"state"`
 	CreatedAt        time.Time   `json:"created_at"`
@@ -24,16 +25,26 @@ type Subscription struct {
 // SubscribeToFormRequest is used when making SubscribeToForm calls.
 type SubscribeToFormRequest struct {
 	// Required
-	FormID int    `json:"-"`
+	FormID FormID `json:"-"`
 	Email  string `json:"email"`
 	// Optional
 	FirstName string            `json:"first_name,omitempty"`
 	Fields    map[string]string `json:"fields,omitempty"`
-	TagIDs    []int             `json:"tags,omitempty"`
+	TagIDs    []TagID           `json:"tags,omitempty"`
+}
+
+// Validate checks the request for missing or malformed fields.
+func (r SubscribeToFormRequest) Validate() error {
+	var v validator
+	v.requireID("FormID", int(r.FormID))
+	v.requireEmail("Email", r.Email)
+	v.tagIDs("TagIDs", r.TagIDs)
+	return v.err()
 }
 
 // SubscribeToFormResponse is the response data from SubscribeToForm.
 type SubscribeToFormResponse struct {
+	RawJSON      `json:"-"`
 	Subscription Subscription `json:"subscription"`
 }
 
@@ -50,14 +61,24 @@ func (c *Client) SubscribeToForm(req SubscribeToFormRequest) (*SubscribeToFormRe
 // FormSubscriptionsRequest is used when making FormSubscriptions calls.
 type FormSubscriptionsRequest struct {
 	// Required
-	FormID int `json:"-"`
+	FormID FormID `json:"-"`
 	// Optional
 	SortOrder       SortOrder       `json:"sort_order,omitempty"`
 	SubscriberState SubscriberState `json:"subscriber_state,omitempty"`
 }
 
+// Validate checks the request for missing or malformed fields.
+func (r FormSubscriptionsRequest) Validate() error {
+	var v validator
+	v.requireID("FormID", int(r.FormID))
+	v.sortOrder("SortOrder", r.SortOrder)
+	v.subscriberState("SubscriberState", r.SubscriberState)
+	return v.err()
+}
+
 // FormSubscriptionsResponse is the response data from FormSubscriptions.
 type FormSubscriptionsResponse struct {
+	RawJSON            `json:"-"`
 	TotalSubscriptions int            `json:"total_subscriptions"`
 	Page               int            `json:"page"`
 	TotalPages         int            `json:"total_pages"`
@@ -77,16 +98,26 @@ func (c *Client) FormSubscriptions(req FormSubscriptionsRequest) (*FormSubscript
 // SubscribeToSequenceRequest is used when making SubscribeToSequence calls.
 type SubscribeToSequenceRequest struct {
 	// Required
-	SequenceID int    `json:"-"`
-	Email      string `json:"email"`
+	SequenceID SequenceID `json:"-"`
+	Email      string     `json:"email"`
 	// Optional
 	FirstName string            `json:"first_name,omitempty"`
 	Fields    map[string]string `json:"fields,omitempty"`
-	TagIDs    []int             `json:"tags,omitempty"`
+	TagIDs    []TagID           `json:"tags,omitempty"`
+}
+
+// Validate checks the request for missing or malformed fields.
+func (r SubscribeToSequenceRequest) Validate() error {
+	var v validator
+	v.requireID("SequenceID", int(r.SequenceID))
+	v.requireEmail("Email", r.Email)
+	v.tagIDs("TagIDs", r.TagIDs)
+	return v.err()
 }
 
 // SubscribeToSequenceResponse is the response data from SubscribeToSequence.
 type SubscribeToSequenceResponse struct {
+	RawJSON      `json:"-"`
 	Subscription Subscription `json:"subscription"`
 }
 
@@ -103,14 +134,24 @@ func (c *Client) SubscribeToSequence(req SubscribeToSequenceRequest) (*Subscribe
 // SequenceSubscriptionsRequest is used when making SequenceSubscriptions calls.
 type SequenceSubscriptionsRequest struct {
 	// Required
-	SequenceID int `json:"-"`
+	SequenceID SequenceID `json:"-"`
 	// Optional
 	SortOrder       SortOrder       `json:"sort_order,omitempty"`
 	SubscriberState SubscriberState `json:"subscriber_state,omitempty"`
 }
 
+// Validate checks the request for missing or malformed fields.
+func (r SequenceSubscriptionsRequest) Validate() error {
+	var v validator
+	v.requireID("SequenceID", int(r.SequenceID))
+	v.sortOrder("SortOrder", r.SortOrder)
+	v.subscriberState("SubscriberState", r.SubscriberState)
+	return v.err()
+}
+
 // SequenceSubscriptionsResponse is the response data from SequenceSubscriptions.
 type SequenceSubscriptionsResponse struct {
+	RawJSON            `json:"-"`
 	TotalSubscriptions int            `json:"total_subscriptions"`
 	Page               int            `json:"page"`
 	TotalPages         int            `json:"total_pages"`
@@ -130,15 +171,26 @@ func (c *Client) SequenceSubscriptions(req SequenceSubscriptionsRequest) (*Seque
 // TagSubscriptionsRequest is used when making TagSubscriptions calls.
 type TagSubscriptionsRequest struct {
 	// Required
-	TagID int `json:"-"`
+	TagID TagID `json:"-"`
 	// Optional
 	SortOrder       SortOrder       `json:"sort_order,omitempty"`
 	SubscriberState SubscriberState `json:"subscriber_state,omitempty"`
 	Page            int             `json:"page,omitempty"`
 }
 
+// Validate checks the request for missing or malformed fields.
+func (r TagSubscriptionsRequest) Validate() error {
+	var v validator
+	v.requireID("TagID", int(r.TagID))
+	v.sortOrder("SortOrder", r.SortOrder)
+	v.subscriberState("SubscriberState", r.SubscriberState)
+	v.nonNegative("Page", r.Page)
+	return v.err()
+}
+
 // TagSubscriptionsResponse is the response data from TagSubscriptions.
 type TagSubscriptionsResponse struct {
+	RawJSON            `json:"-"`
 	TotalSubscriptions int            `json:"total_subscriptions"`
 	Page               int            `json:"page"`
 	TotalPages         int            `json:"total_pages"`
@@ -158,17 +210,27 @@ func (c *Client) TagSubscriptions(req TagSubscriptionsRequest) (*TagSubscription
 // TagSubscriberRequest is used when making TagSubscriber calls.
 type TagSubscriberRequest struct {
 	// Required
-	TagID int    `json:"-"`
+	TagID TagID  `json:"-"`
 	Email string `json:"email"`
 	// Optional
 	FirstName string            `json:"first_name,omitempty"`
 	Fields    map[string]string `json:"fields,omitempty"`
 	// Additional TagIDs you wish to apply to the user.
-	TagIDs []int `json:"tags,omitempty"`
+	TagIDs []TagID `json:"tags,omitempty"`
+}
+
+// Validate checks the request for missing or malformed fields.
+func (r TagSubscriberRequest) Validate() error {
+	var v validator
+	v.requireID("TagID", int(r.TagID))
+	v.requireEmail("Email", r.Email)
+	v.tagIDs("TagIDs", r.TagIDs)
+	return v.err()
 }
 
 // TagSubscriberResponse is the response data from TagSubscriber.
 type TagSubscriberResponse struct {
+	RawJSON      `json:"-"`
 	Subscription Subscription `json:"subscription"`
 }
 
@@ -185,13 +247,22 @@ func (c *Client) TagSubscriber(req TagSubscriberRequest) (*TagSubscriberResponse
 // UntagSubscriberRequest is used when making UntagSubscriber calls.
 type UntagSubscriberRequest struct {
 	// Required
-	SubscriberID int `json:"-"`
-	TagID        int `json:"-"`
+	SubscriberID SubscriberID `json:"-"`
+	TagID        TagID        `json:"-"`
+}
+
+// Validate checks the request for missing or malformed fields.
+func (r UntagSubscriberRequest) Validate() error {
+	var v validator
+	v.requireID("SubscriberID", int(r.SubscriberID))
+	v.requireID("TagID", int(r.TagID))
+	return v.err()
 }
 
 // UntagSubscriberResponse is the response data from UntagSubscriber.
 type UntagSubscriberResponse struct {
-	Tag Tag
+	RawJSON `json:"-"`
+	Tag     Tag
 }
 
 func (usr *UntagSubscriberResponse) UnmarshalJSON(b []byte) error {
@@ -204,14 +275,16 @@ func (usr *UntagSubscriberResponse) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (usr *UntagSubscriberResponse) remapJSON(raw json.RawMessage) map[string]json.RawMessage {
+	return map[string]json.RawMessage{"Tag": raw}
+}
+
 // UntagSubscriber will subscribe an email address to a form.
 func (c *Client) UntagSubscriber(req UntagSubscriberRequest) (*UntagSubscriberResponse, error) {
 	var ret UntagSubscriberResponse
-	var tag Tag
 	err := c.Do(http.MethodDelete, fmt.Sprintf("subscribers/%v/tags/%v", req.SubscriberID, req.TagID), req, &ret)
 	if err != nil {
 		return nil, err
 	}
-	ret.Tag = tag
 	return &ret, nil
 }
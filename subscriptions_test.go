@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
 )
 
 func TestClient_SubscribeToForm(t *testing.T) {
@@ -21,6 +22,19 @@ func TestClient_SubscribeToForm(t *testing.T) {
 			t.Errorf("Subscriber.ID = %v; want 1", resp.Subscription.Subscriber.ID)
 		}
 	})
+	t.Run("request", func(t *testing.T) {
+		exp := convertkittest.Expect(t, "testdata", "POST_forms_213_subscribe")
+		c := clientWithHandler(t, exp.Handler(baseHandler(t, "fake-secret-key")).ServeHTTP)
+		c.Secret = "fake-secret-key"
+		_, err := c.SubscribeToForm(convertkit.SubscribeToFormRequest{
+			FormID: 213,
+			Email:  "jonsnow@example.com",
+			TagIDs: convertkit.TagIDs(14),
+		})
+		if err != nil {
+			t.Fatalf("SubscribeToForm() err = %v; want %v", err, nil)
+		}
+	})
 }
 
 func TestClient_FormSubscriptions(t *testing.T) {
@@ -105,12 +119,15 @@ func TestClient_TagSubscriberSequence(t *testing.T) {
 func TestClient_UntagSubscriberSequence(t *testing.T) {
 	c := client(t, "fake-secret-key")
 	t.Run("basic check", func(t *testing.T) {
-		_, err := c.UntagSubscriber(convertkit.UntagSubscriberRequest{
+		resp, err := c.UntagSubscriber(convertkit.UntagSubscriberRequest{
 			SubscriberID: 88,
 			TagID:        71,
 		})
 		if err != nil {
 			t.Fatalf("UntagSubscriber() err = %v; want %v", err, nil)
 		}
+		if resp.Tag.Name != "House Stark" {
+			t.Errorf("Tag.Name = %v; want %v", resp.Tag.Name, "House Stark")
+		}
 	})
 }
@@ -1,9 +1,11 @@
 package convertkit
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -12,14 +14,16 @@ import (
 // beginner-oriented emails, or you might tag them as interested in a paid
 // course so they get information about future sales.
 type Tag struct {
-	ID        int       `json:"id"`
+	Raw       *RawJSON  `json:"-"`
+	ID        TagID     `json:"id"`
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // TagsResponse is the response data from Tags.
 type TagsResponse struct {
-	Tags []Tag `json:"tags"`
+	RawJSON `json:"-"`
+	Tags    []Tag `json:"tags"`
 }
 
 // Tags lists the sequences from your account.
@@ -34,7 +38,8 @@ func (c *Client) Tags() (*TagsResponse, error) {
 
 // CreateTagsResponse is the data returned from a CreateTags call.
 type CreateTagsResponse struct {
-	Tags []Tag
+	RawJSON `json:"-"`
+	Tags    []Tag
 }
 
 // UnmarshalJSON implements json.Unmarshaler
@@ -59,6 +64,14 @@ func (ctr *CreateTagsResponse) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+func (ctr *CreateTagsResponse) remapJSON(raw json.RawMessage) map[string]json.RawMessage {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '{' {
+		raw = append(append(json.RawMessage("["), raw...), ']')
+	}
+	return map[string]json.RawMessage{"Tags": raw}
+}
+
 func (ctr *CreateTagsResponse) unmarshalSingle(b []byte) error {
 	var t Tag
 	err := json.Unmarshal(b, &t)
@@ -79,14 +92,30 @@ func (ctr *CreateTagsResponse) unmarshalMany(b []byte) error {
 	return nil
 }
 
-// CreateTags will create tags using the provided values as their names.
-func (c *Client) CreateTags(tags ...string) (*CreateTagsResponse, error) {
-	type newTag struct {
-		Name string `json:"name"`
+type newTag struct {
+	Name string `json:"name"`
+}
+
+type createTagsRequest struct {
+	Tags []newTag `json:"tag"`
+}
+
+func (r createTagsRequest) Validate() error {
+	var v validator
+	if len(r.Tags) == 0 {
+		v.add("Tags", "must contain at least one tag name")
 	}
-	var data struct {
-		Tags []newTag `json:"tag"`
+	for i, t := range r.Tags {
+		if strings.TrimSpace(t.Name) == "" {
+			v.add(fmt.Sprintf("Tags[%d]", i), "must not be blank")
+		}
 	}
+	return v.err()
+}
+
+// CreateTags will create tags using the provided values as their names.
+func (c *Client) CreateTags(tags ...string) (*CreateTagsResponse, error) {
+	var data createTagsRequest
 	for _, tag := range tags {
 		data.Tags = append(data.Tags, newTag{tag})
 	}
@@ -98,6 +127,89 @@ func (c *Client) CreateTags(tags ...string) (*CreateTagsResponse, error) {
 	return &ret, nil
 }
 
+// EnsureTags returns the tags with the given names, creating any that don't
+// exist yet with a single CreateTags call. Names are matched
+// case-insensitively, ignoring leading and trailing spaces, and the returned
+// map is keyed by the names as given. If an account already has several tags
+// whose names only differ by case, the one matching exactly is preferred,
+// followed by the oldest.
+//
+// Calls made with the same Client are serialized, so concurrent callers
+// sharing a Client never create duplicate tags. Separate Clients aren't
+// coordinated, even when they use the same account, so share a single Client
+// between everything that might create the same tags.
+func (c *Client) EnsureTags(names ...string) (map[string]Tag, error) {
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("ensuring tags: tag names must not be blank")
+		}
+	}
+	mu := &c.state().ensureTagsMu
+	mu.Lock()
+	defer mu.Unlock()
+
+	resp, err := c.Tags()
+	if err != nil {
+		return nil, err
+	}
+	tags := resp.Tags
+	var missing []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		key := tagKey(name)
+		if _, ok := findTag(tags, name); ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		missing = append(missing, strings.TrimSpace(name))
+	}
+	if len(missing) > 0 {
+		created, err := c.CreateTags(missing...)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, created.Tags...)
+	}
+	ret := make(map[string]Tag, len(names))
+	for _, name := range names {
+		tag, ok := findTag(tags, name)
+		if !ok {
+			return nil, fmt.Errorf("ensuring tags: %q missing from the CreateTags response", name)
+		}
+		ret[name] = tag
+	}
+	return ret, nil
+}
+
+// tagKey is used to compare tag names.
+func tagKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// findTag returns the tag with the given name, preferring an exact match over
+// a case-insensitive one and older tags over newer ones.
+func findTag(tags []Tag, name string) (Tag, bool) {
+	var ret Tag
+	found := false
+	for _, tag := range tags {
+		if tagKey(tag.Name) != tagKey(name) {
+			continue
+		}
+		if found {
+			exact := strings.TrimSpace(tag.Name) == strings.TrimSpace(name)
+			retExact := strings.TrimSpace(ret.Name) == strings.TrimSpace(name)
+			if retExact && !exact {
+				continue
+			}
+			if exact == retExact && tag.ID > ret.ID {
+				continue
+			}
+		}
+		ret, found = tag, true
+	}
+	return ret, found
+}
+
 // Delete isn't supported by the API despite the UI doing it via a path similar to this.
 // // DeleteTag will create tags using the provided values as their names.
 // func (c *Client) DeleteTag(id int, name string) (interface{}, error) {
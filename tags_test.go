@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/convertkittest"
 )
 
 func TestClient_Tags(t *testing.T) {
@@ -158,3 +160,103 @@ func TestCreateTagsResponse_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+// countingClient counts the requests sent through it by method.
+type countingClient struct {
+	convertkittest.HTTPClient
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (cc *countingClient) Do(r *http.Request) (*http.Response, error) {
+	cc.mu.Lock()
+	if cc.counts == nil {
+		cc.counts = make(map[string]int)
+	}
+	cc.counts[r.Method]++
+	cc.mu.Unlock()
+	return cc.HTTPClient.Do(r)
+}
+
+func TestClient_EnsureTags(t *testing.T) {
+	srv := convertkittest.NewServer("fake-secret-key")
+	t.Cleanup(srv.Close)
+	srv.Seed(convertkittest.Fixtures{
+		Tags: []convertkit.Tag{
+			{ID: 14, Name: "Customer"},
+			{ID: 15, Name: "beta"},
+			{ID: 16, Name: "Beta"},
+		},
+	})
+	c := srv.Client()
+	cc := &countingClient{HTTPClient: c.HTTPClient}
+	c.HTTPClient = cc
+
+	got, err := c.EnsureTags("customer", "Beta", "BETA", "New", " new ", "Other")
+	if err != nil {
+		t.Fatalf("EnsureTags() err = %v; want nil", err)
+	}
+	want := map[string]convertkit.TagID{
+		"customer": 14,
+		"Beta":     16,
+		"BETA":     15,
+	}
+	for name, id := range want {
+		if got[name].ID != id {
+			t.Errorf("EnsureTags()[%q].ID = %v; want %v", name, got[name].ID, id)
+		}
+	}
+	if got["New"].ID == 0 || got["New"].ID != got[" new "].ID {
+		t.Errorf("EnsureTags() New = %v, \" new \" = %v; want the same new tag", got["New"].ID, got[" new "].ID)
+	}
+	if got["Other"].ID == 0 || got["Other"].Name != "Other" {
+		t.Errorf("EnsureTags()[Other] = %+v; want a new tag named Other", got["Other"])
+	}
+	if cc.counts[http.MethodPost] != 1 {
+		t.Errorf("CreateTags calls = %d; want 1", cc.counts[http.MethodPost])
+	}
+	if n := len(srv.Tags()); n != 5 {
+		t.Errorf("len(Tags()) = %d; want 5", n)
+	}
+
+	t.Run("nothing missing", func(t *testing.T) {
+		cc.counts = nil
+		_, err := c.EnsureTags("Customer", "new")
+		if err != nil {
+			t.Fatalf("EnsureTags() err = %v; want nil", err)
+		}
+		if cc.counts[http.MethodPost] != 0 {
+			t.Errorf("CreateTags calls = %d; want 0", cc.counts[http.MethodPost])
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		ids := make([]convertkit.TagID, 10)
+		for i := range ids {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				got, err := c.EnsureTags("Launch")
+				if err != nil {
+					t.Errorf("EnsureTags() err = %v; want nil", err)
+				}
+				ids[i] = got["Launch"].ID
+			}(i)
+		}
+		wg.Wait()
+		for _, id := range ids {
+			if id != ids[0] {
+				t.Errorf("EnsureTags() IDs = %v; want them all the same", ids)
+				break
+			}
+		}
+	})
+
+	t.Run("blank", func(t *testing.T) {
+		_, err := c.EnsureTags("ok", " ")
+		if err == nil {
+			t.Errorf("EnsureTags() err = nil; want an error")
+		}
+	})
+}
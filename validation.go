@@ -0,0 +1,125 @@
+package convertkit
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// FieldError describes a single request field that failed validation.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (fe FieldError) String() string {
+	return fmt.Sprintf("%s %s", fe.Field, fe.Message)
+}
+
+// ValidationError is returned when a request fails client-side validation.
+// When this happens no API call is made. Fields lists every offending field,
+// not just the first one found.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, fe := range e.Fields {
+		msgs[i] = fe.String()
+	}
+	return fmt.Sprintf("invalid request: %s", strings.Join(msgs, "; "))
+}
+
+// validatable is implemented by request types that can check themselves before
+// being sent to the API. Client.Do will call Validate on any params that
+// implement it.
+type validatable interface {
+	Validate() error
+}
+
+// validator accumulates FieldErrors so that every problem with a request can
+// be reported at once.
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field, msg string) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: msg})
+}
+
+func (v *validator) requireID(field string, id int) {
+	if id <= 0 {
+		v.add(field, "is required and must be greater than 0")
+	}
+}
+
+func (v *validator) requireEmail(field, email string) {
+	if email == "" {
+		v.add(field, "is required")
+		return
+	}
+	v.email(field, email)
+}
+
+// email validates an optional email address. Empty values are ignored.
+func (v *validator) email(field, email string) {
+	if email == "" {
+		return
+	}
+	if !validEmail(email) {
+		v.add(field, fmt.Sprintf("%q is not a valid email address", email))
+	}
+}
+
+func (v *validator) tagIDs(field string, ids []TagID) {
+	for i, id := range ids {
+		if id <= 0 {
+			v.add(fmt.Sprintf("%s[%d]", field, i), "must be greater than 0")
+		}
+	}
+}
+
+func (v *validator) nonNegative(field string, n int) {
+	if n < 0 {
+		v.add(field, "must not be negative")
+	}
+}
+
+func (v *validator) sortOrder(field string, so SortOrder) {
+	switch so {
+	case "", SortOldToNew, SortNewToOld:
+	default:
+		v.add(field, fmt.Sprintf("%q is not a valid sort order", so))
+	}
+}
+
+func (v *validator) subscriberState(field string, state SubscriberState) {
+	switch state {
+	case "", SubscriberStateActive, SubscriberStateCancelled:
+	default:
+		v.add(field, fmt.Sprintf("%q is not a valid subscriber state", state))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return ValidationError{Fields: v.errs}
+}
+
+// validEmail performs a syntactic check of an email address. It does not
+// accept display names (eg "Jon <jon@example.com>") since the API expects a
+// bare address.
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
+	if addr.Address != email || addr.Name != "" {
+		return false
+	}
+	at := strings.LastIndex(email, "@")
+	return at > 0 && at < len(email)-1
+}
@@ -0,0 +1,111 @@
+package convertkit_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func TestRequest_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req  interface{ Validate() error }
+		want []string
+	}{
+		"SubscribeToForm valid": {
+			req: convertkit.SubscribeToFormRequest{FormID: 213, Email: "jonsnow@example.com"},
+		},
+		"SubscribeToForm missing everything": {
+			req:  convertkit.SubscribeToFormRequest{},
+			want: []string{"FormID", "Email"},
+		},
+		"SubscribeToForm bad email": {
+			req:  convertkit.SubscribeToFormRequest{FormID: 213, Email: "jonsnow"},
+			want: []string{"Email"},
+		},
+		"SubscribeToForm display name": {
+			req:  convertkit.SubscribeToFormRequest{FormID: 213, Email: "Jon <jonsnow@example.com>"},
+			want: []string{"Email"},
+		},
+		"SubscribeToForm bad tag": {
+			req: convertkit.SubscribeToFormRequest{
+				FormID: 213,
+				Email:  "jonsnow@example.com",
+				TagIDs: []convertkit.TagID{1, 0},
+			},
+			want: []string{"TagIDs[1]"},
+		},
+		"SubscribeToSequence missing ID": {
+			req:  convertkit.SubscribeToSequenceRequest{Email: "jonsnow@example.com"},
+			want: []string{"SequenceID"},
+		},
+		"TagSubscriber missing email": {
+			req:  convertkit.TagSubscriberRequest{TagID: 14},
+			want: []string{"Email"},
+		},
+		"UntagSubscriber missing IDs": {
+			req:  convertkit.UntagSubscriberRequest{},
+			want: []string{"SubscriberID", "TagID"},
+		},
+		"UpdateSubscriber email optional": {
+			req: convertkit.UpdateSubscriberRequest{SubscriberID: 123},
+		},
+		"UpdateSubscriber bad email": {
+			req:  convertkit.UpdateSubscriberRequest{SubscriberID: 123, Email: "@example.com"},
+			want: []string{"Email"},
+		},
+		"FormSubscriptions bad sort": {
+			req:  convertkit.FormSubscriptionsRequest{FormID: 213, SortOrder: "sideways"},
+			want: []string{"SortOrder"},
+		},
+		"TagSubscriptions bad state": {
+			req:  convertkit.TagSubscriptionsRequest{TagID: 55, SubscriberState: "asleep", Page: -1},
+			want: []string{"SubscriberState", "Page"},
+		},
+		"Subscribers valid": {
+			req: convertkit.SubscribersRequest{Email: "test@user.com", SortOrder: convertkit.SortNewToOld},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.req.Validate()
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() err = %v; want nil", err)
+				}
+				return
+			}
+			var vErr convertkit.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("Validate() err type = %T; want %T", err, vErr)
+			}
+			var got []string
+			for _, fe := range vErr.Fields {
+				got = append(got, fe.Field)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Fields = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClient_Do_validates(t *testing.T) {
+	c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+	})
+	_, err := c.SubscribeToForm(convertkit.SubscribeToFormRequest{})
+	var vErr convertkit.ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("SubscribeToForm() err type = %T; want %T", err, vErr)
+	}
+	_, err = c.UnsubscribeSubscriber("not-an-email")
+	if !errors.As(err, &vErr) {
+		t.Fatalf("UnsubscribeSubscriber() err type = %T; want %T", err, vErr)
+	}
+	_, err = c.CreateTags()
+	if !errors.As(err, &vErr) {
+		t.Fatalf("CreateTags() err type = %T; want %T", err, vErr)
+	}
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultDedupWindow is the default Deduper.Window.
+const DefaultDedupWindow = time.Hour
+
+// Fingerprint returns a stable key identifying the logical event in a
+// delivery. It is built from the event type, the subscriber (or purchase) ID,
+// the subject of the event (eg the tag ID), and the time bucket the delivery
+// was received in. Retries of the same event within a bucket share a
+// fingerprint.
+func Fingerprint(d Delivery, bucket time.Time) (string, error) {
+	var p Payload
+	err := json.Unmarshal(d.Body, &p)
+	if err != nil {
+		return "", fmt.Errorf("webhook: decoding payload: %w", err)
+	}
+	var who string
+	switch {
+	case p.Purchase != nil:
+		who = "purchase:" + strconv.Itoa(p.Purchase.ID)
+	case p.Subscriber != nil:
+		who = "subscriber:" + strconv.Itoa(int(p.Subscriber.ID))
+	default:
+		return "", fmt.Errorf("webhook: %s payload has no subscriber or purchase", d.Event.Name)
+	}
+	subject := fmt.Sprintf("form:%d|sequence:%d|tag:%d|product:%d|link:%s",
+		d.Event.FormID, d.Event.SequenceID, d.Event.TagID, d.Event.ProductID, d.Event.InitiatorValue)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", d.Event.Name, who, subject, bucket.Unix())))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Deduper is middleware that ensures each logical webhook event is only
+// processed once, even when Convert Kit retries a delivery.
+//
+// Deliveries are bucketed by the time they are received, and a delivery is
+// treated as a duplicate if the same event was processed in the current or
+// previous bucket. Deliveries that fail (non-2xx response) are released so
+// that Convert Kit's retry is processed.
+type Deduper struct {
+	// Store records seen events. Required.
+	Store SeenStore
+	// Window is the size of each time bucket. Defaults to DefaultDedupWindow.
+	Window time.Duration
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// OnDuplicate, if set, is called for every duplicate delivery dropped.
+	OnDuplicate func(r *http.Request, d Delivery)
+}
+
+func (dd *Deduper) window() time.Duration {
+	if dd.Window <= 0 {
+		return DefaultDedupWindow
+	}
+	return dd.Window
+}
+
+func (dd *Deduper) now() time.Time {
+	if dd.Now != nil {
+		return dd.Now()
+	}
+	return time.Now()
+}
+
+// Middleware returns an http.Handler that only calls next for deliveries that
+// haven't already been processed. Duplicates are acknowledged with a 204.
+func (dd *Deduper) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d, err := ReadDelivery(r)
+		if err != nil {
+			// Let next decide how to respond to invalid deliveries.
+			next.ServeHTTP(w, r)
+			return
+		}
+		window := dd.window()
+		bucket := dd.now().Truncate(window)
+		key, err := Fingerprint(*d, bucket)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		prevKey, err := Fingerprint(*d, bucket.Add(-window))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		dup, err := dd.Store.Claim(key, 2*window, prevKey)
+		if err != nil {
+			status := http.StatusInternalServerError
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		if dup {
+			if dd.OnDuplicate != nil {
+				dd.OnDuplicate(r, *d)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		sw := &statusWriter{ResponseWriter: w}
+		defer func() {
+			if sw.failed() {
+				// Errors here just mean the retry will be treated as a duplicate.
+				_ = dd.Store.Release(key)
+			}
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// statusWriter records the status code written to a ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if sw.status == 0 {
+		sw.status = status
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	return sw.ResponseWriter.Write(b)
+}
+
+func (sw *statusWriter) failed() bool {
+	return sw.status != 0 && (sw.status < 200 || sw.status > 299)
+}
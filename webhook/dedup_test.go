@@ -0,0 +1,155 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func TestDeduper(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	tagAdd := convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14}
+
+	var mu sync.Mutex
+	var calls int
+	var fail bool
+	var h webhook.Handler
+	h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	dd := &webhook.Deduper{
+		Store:  &webhook.MemoryStore{Now: clock},
+		Window: time.Hour,
+		Now:    clock,
+	}
+	handler := dd.Middleware(&h)
+	deliver := func(event convertkit.WebhookEvent, body []byte) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, deliveryRequest(t, event, body))
+		return w.Code
+	}
+	sub := fixture(t, "subscriber")
+
+	if got := deliver(tagAdd, sub); got != http.StatusNoContent {
+		t.Errorf("first delivery StatusCode = %d; want %d", got, http.StatusNoContent)
+	}
+	now = now.Add(10 * time.Minute)
+	if got := deliver(tagAdd, sub); got != http.StatusNoContent {
+		t.Errorf("retry StatusCode = %d; want %d", got, http.StatusNoContent)
+	}
+	if calls != 1 {
+		t.Errorf("calls after retry = %d; want 1", calls)
+	}
+
+	// Crossing into the next bucket still counts as a duplicate.
+	now = now.Add(time.Hour)
+	deliver(tagAdd, sub)
+	if calls != 1 {
+		t.Errorf("calls after next bucket = %d; want 1", calls)
+	}
+
+	// A different tag is a different event.
+	deliver(convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 15}, sub)
+	if calls != 2 {
+		t.Errorf("calls after different tag = %d; want 2", calls)
+	}
+
+	// Much later, the same event is processed again.
+	now = now.Add(3 * time.Hour)
+	deliver(tagAdd, sub)
+	if calls != 3 {
+		t.Errorf("calls after window = %d; want 3", calls)
+	}
+
+	// Failed deliveries are retried.
+	fail = true
+	other := convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberActivate}
+	h.OnSubscriberActivate(func(ctx context.Context, e webhook.SubscriberActivateEvent) error {
+		calls++
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if got := deliver(other, sub); got != http.StatusInternalServerError {
+		t.Errorf("failed delivery StatusCode = %d; want %d", got, http.StatusInternalServerError)
+	}
+	fail = false
+	if got := deliver(other, sub); got != http.StatusNoContent {
+		t.Errorf("retried delivery StatusCode = %d; want %d", got, http.StatusNoContent)
+	}
+	if calls != 5 {
+		t.Errorf("calls after failed retry = %d; want 5", calls)
+	}
+}
+
+func TestDeduper_concurrent(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	var h webhook.Handler
+	h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return nil
+	})
+	dd := &webhook.Deduper{Store: &webhook.MemoryStore{}}
+	handler := dd.Middleware(&h)
+	target, _ := webhook.TargetURL("https://example.com/webhooks", convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14})
+	body := fixture(t, "subscriber")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("calls = %d; want 1", calls)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	bucket := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := webhook.Delivery{
+		Event: convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14},
+		Body:  fixture(t, "subscriber"),
+	}
+	a, err := webhook.Fingerprint(d, bucket)
+	if err != nil {
+		t.Fatalf("Fingerprint() err = %v; want nil", err)
+	}
+	// Formatting differences in the body don't matter.
+	d2 := d
+	d2.Body = []byte(`{"subscriber":{"id":1,"email_address":"jonsnow@example.com"}}`)
+	b, _ := webhook.Fingerprint(d2, bucket)
+	if a != b {
+		t.Errorf("Fingerprint() differs for the same subscriber")
+	}
+	c, _ := webhook.Fingerprint(d, bucket.Add(time.Hour))
+	if a == c {
+		t.Errorf("Fingerprint() is the same for different buckets")
+	}
+	_, err = webhook.Fingerprint(webhook.Delivery{Event: d.Event, Body: []byte(`{}`)}, bucket)
+	if err == nil {
+		t.Errorf("Fingerprint() err = nil; want error for empty payload")
+	}
+}
@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// Event is implemented by every typed webhook event.
+type Event interface {
+	// EventName returns the name of the Convert Kit event.
+	EventName() convertkit.WebhookEventName
+}
+
+// SubscriberActivateEvent is sent when a subscriber is activated.
+type SubscriberActivateEvent struct {
+	Subscriber convertkit.Subscriber
+}
+
+// SubscriberUnsubscribeEvent is sent when a subscriber unsubscribes.
+type SubscriberUnsubscribeEvent struct {
+	Subscriber convertkit.Subscriber
+}
+
+// SubscriberBounceEvent is sent when an email to a subscriber bounces.
+type SubscriberBounceEvent struct {
+	Subscriber convertkit.Subscriber
+}
+
+// SubscriberComplainEvent is sent when a subscriber marks an email as spam.
+type SubscriberComplainEvent struct {
+	Subscriber convertkit.Subscriber
+}
+
+// FormSubscribeEvent is sent when a subscriber joins a form. Only the Form ID
+// is known, since it comes from the registered webhook rather than the payload.
+type FormSubscribeEvent struct {
+	Subscriber convertkit.Subscriber
+	Form       convertkit.Form
+}
+
+// SequenceSubscribeEvent is sent when a subscriber joins a sequence. Only the
+// Sequence ID is known.
+type SequenceSubscribeEvent struct {
+	Subscriber convertkit.Subscriber
+	Sequence   convertkit.Sequence
+}
+
+// SequenceCompleteEvent is sent when a subscriber completes a sequence. Only
+// the Sequence ID is known.
+type SequenceCompleteEvent struct {
+	Subscriber convertkit.Subscriber
+	Sequence   convertkit.Sequence
+}
+
+// TagAddEvent is sent when a tag is added to a subscriber. Only the Tag ID is
+// known.
+type TagAddEvent struct {
+	Subscriber convertkit.Subscriber
+	Tag        convertkit.Tag
+}
+
+// TagRemoveEvent is sent when a tag is removed from a subscriber. Only the Tag
+// ID is known.
+type TagRemoveEvent struct {
+	Subscriber convertkit.Subscriber
+	Tag        convertkit.Tag
+}
+
+// LinkClickEvent is sent when a subscriber clicks a link.
+type LinkClickEvent struct {
+	Subscriber convertkit.Subscriber
+	URL        string
+}
+
+// ProductPurchaseEvent is sent when a subscriber purchases a product.
+type ProductPurchaseEvent struct {
+	Subscriber convertkit.Subscriber
+	ProductID  int
+}
+
+// PurchaseCreateEvent is sent when a purchase is created.
+type PurchaseCreateEvent struct {
+	Purchase convertkit.Purchase
+}
+
+// EventName implements Event.
+func (SubscriberActivateEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookSubscriberActivate
+}
+
+// EventName implements Event.
+func (SubscriberUnsubscribeEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookSubscriberUnsubscribe
+}
+
+// EventName implements Event.
+func (SubscriberBounceEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookSubscriberBounce
+}
+
+// EventName implements Event.
+func (SubscriberComplainEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookSubscriberComplain
+}
+
+// EventName implements Event.
+func (FormSubscribeEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookFormSubscribe
+}
+
+// EventName implements Event.
+func (SequenceSubscribeEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookSequenceSubscribe
+}
+
+// EventName implements Event.
+func (SequenceCompleteEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookSequenceComplete
+}
+
+// EventName implements Event.
+func (TagAddEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookTagAdd
+}
+
+// EventName implements Event.
+func (TagRemoveEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookTagRemove
+}
+
+// EventName implements Event.
+func (LinkClickEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookLinkClick
+}
+
+// EventName implements Event.
+func (ProductPurchaseEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookProductPurchase
+}
+
+// EventName implements Event.
+func (PurchaseCreateEvent) EventName() convertkit.WebhookEventName {
+	return convertkit.WebhookPurchaseCreate
+}
+
+// Payload is the JSON body Convert Kit sends with a webhook. Purchase events
+// set Purchase, and every other event sets Subscriber.
+type Payload struct {
+	Subscriber *convertkit.Subscriber `json:"subscriber,omitempty"`
+	Purchase   *convertkit.Purchase   `json:"purchase,omitempty"`
+}
+
+// Decode decodes a webhook body into the typed Event for the provided webhook
+// event.
+func Decode(event convertkit.WebhookEvent, body []byte) (Event, error) {
+	var p Payload
+	err := json.Unmarshal(body, &p)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: decoding payload: %w", err)
+	}
+	if event.Name == convertkit.WebhookPurchaseCreate {
+		if p.Purchase == nil {
+			return nil, fmt.Errorf("webhook: %s payload is missing purchase", event.Name)
+		}
+		return PurchaseCreateEvent{Purchase: *p.Purchase}, nil
+	}
+	if p.Subscriber == nil {
+		return nil, fmt.Errorf("webhook: %s payload is missing subscriber", event.Name)
+	}
+	sub := *p.Subscriber
+	switch event.Name {
+	case convertkit.WebhookSubscriberActivate:
+		return SubscriberActivateEvent{Subscriber: sub}, nil
+	case convertkit.WebhookSubscriberUnsubscribe:
+		return SubscriberUnsubscribeEvent{Subscriber: sub}, nil
+	case convertkit.WebhookSubscriberBounce:
+		return SubscriberBounceEvent{Subscriber: sub}, nil
+	case convertkit.WebhookSubscriberComplain:
+		return SubscriberComplainEvent{Subscriber: sub}, nil
+	case convertkit.WebhookFormSubscribe:
+		return FormSubscribeEvent{Subscriber: sub, Form: convertkit.Form{ID: event.FormID}}, nil
+	case convertkit.WebhookSequenceSubscribe:
+		return SequenceSubscribeEvent{Subscriber: sub, Sequence: convertkit.Sequence{ID: event.SequenceID}}, nil
+	case convertkit.WebhookSequenceComplete:
+		return SequenceCompleteEvent{Subscriber: sub, Sequence: convertkit.Sequence{ID: event.SequenceID}}, nil
+	case convertkit.WebhookTagAdd:
+		return TagAddEvent{Subscriber: sub, Tag: convertkit.Tag{ID: event.TagID}}, nil
+	case convertkit.WebhookTagRemove:
+		return TagRemoveEvent{Subscriber: sub, Tag: convertkit.Tag{ID: event.TagID}}, nil
+	case convertkit.WebhookLinkClick:
+		return LinkClickEvent{Subscriber: sub, URL: event.InitiatorValue}, nil
+	case convertkit.WebhookProductPurchase:
+		return ProductPurchaseEvent{Subscriber: sub, ProductID: event.ProductID}, nil
+	}
+	return nil, fmt.Errorf("webhook: unsupported event %q", event.Name)
+}
@@ -0,0 +1,299 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/internal/atomicfile"
+)
+
+// Rule is a webhook that should exist.
+type Rule struct {
+	Event     convertkit.WebhookEvent `json:"event"`
+	TargetURL string                  `json:"target_url"`
+}
+
+func (r Rule) String() string {
+	var parts []string
+	e := r.Event
+	parts = append(parts, string(e.Name))
+	if e.FormID != 0 {
+		parts = append(parts, fmt.Sprintf("form_id=%d", e.FormID))
+	}
+	if e.SequenceID != 0 {
+		parts = append(parts, fmt.Sprintf("sequence_id=%d", e.SequenceID))
+	}
+	if e.TagID != 0 {
+		parts = append(parts, fmt.Sprintf("tag_id=%d", e.TagID))
+	}
+	if e.ProductID != 0 {
+		parts = append(parts, fmt.Sprintf("product_id=%d", e.ProductID))
+	}
+	if e.InitiatorValue != "" {
+		parts = append(parts, fmt.Sprintf("initiator_value=%s", e.InitiatorValue))
+	}
+	return fmt.Sprintf("%s -> %s", strings.Join(parts, " "), r.TargetURL)
+}
+
+// State is the local record of webhooks registered with Convert Kit. The v3
+// API has no way to list webhooks, so the Reconciler keeps track of them.
+type State struct {
+	Rules []convertkit.WebhookRule `json:"rules"`
+}
+
+// LoadState reads the State from path. A missing file results in an empty
+// State.
+func LoadState(path string) (*State, error) {
+	var s State
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return &s, nil
+	case err != nil:
+		return nil, fmt.Errorf("webhook: reading state: %w", err)
+	}
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: decoding state: %w", err)
+	}
+	return &s, nil
+}
+
+// Save writes the State to path. The file is replaced in one step, so a
+// failed write never loses track of the webhooks already registered.
+func (s *State) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("webhook: encoding state: %w", err)
+	}
+	err = atomicfile.Write(path, func(w io.Writer) error {
+		_, err := w.Write(append(b, '\n'))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: writing state: %w", err)
+	}
+	return nil
+}
+
+// Action is what a Change will do.
+type Action string
+
+// Actions a Plan can contain.
+const (
+	ActionCreate Action = "create"
+	ActionDelete Action = "delete"
+)
+
+// Change is a single step in a Plan.
+type Change struct {
+	Action Action
+	Rule   Rule
+	// RuleID is the ID of the webhook being deleted.
+	RuleID convertkit.WebhookRuleID
+}
+
+func (c Change) String() string {
+	if c.Action == ActionDelete {
+		return fmt.Sprintf("- %s (rule %d)", c.Rule, c.RuleID)
+	}
+	return fmt.Sprintf("+ %s", c.Rule)
+}
+
+// ErrStateChanged is returned by Apply when the state file no longer matches
+// the state a Plan was made from.
+var ErrStateChanged = errors.New("webhook: state changed since the plan was made")
+
+// Plan is the set of changes needed to make the registered webhooks match the
+// desired webhooks.
+type Plan struct {
+	Changes []Change
+	// Unchanged lists the rules that already exist.
+	Unchanged []convertkit.WebhookRule
+
+	// base is the state the plan was made from.
+	base []convertkit.WebhookRule
+}
+
+// Empty reports whether the plan has no changes.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns a diff-like description of the plan, suitable for a dry run.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes. Webhooks are up to date.\n"
+	}
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		sb.WriteString(c.String())
+		sb.WriteString("\n")
+	}
+	var creates, deletes int
+	for _, c := range p.Changes {
+		if c.Action == ActionCreate {
+			creates++
+		} else {
+			deletes++
+		}
+	}
+	fmt.Fprintf(&sb, "Plan: %d to create, %d to delete, %d unchanged.\n", creates, deletes, len(p.Unchanged))
+	return sb.String()
+}
+
+// Diff compares the webhooks in state with the desired webhooks and returns
+// the Plan needed to converge them. Duplicate rules are collapsed, so a
+// desired rule that appears twice is only created once, and a rule
+// registered twice has the extra copy deleted.
+func Diff(state State, desired []Rule) *Plan {
+	p := Plan{base: append([]convertkit.WebhookRule(nil), state.Rules...)}
+	want := make(map[Rule]bool, len(desired))
+	var order []Rule
+	for _, r := range desired {
+		if !want[r] {
+			want[r] = true
+			order = append(order, r)
+		}
+	}
+	have := make(map[Rule]bool, len(state.Rules))
+	for _, wr := range state.Rules {
+		r := Rule{Event: wr.Event, TargetURL: wr.TargetURL}
+		if want[r] && !have[r] {
+			have[r] = true
+			p.Unchanged = append(p.Unchanged, wr)
+			continue
+		}
+		p.Changes = append(p.Changes, Change{
+			Action: ActionDelete,
+			Rule:   r,
+			RuleID: wr.ID,
+		})
+	}
+	var creates []Change
+	for _, r := range order {
+		if !have[r] {
+			creates = append(creates, Change{Action: ActionCreate, Rule: r})
+		}
+	}
+	// Creates go first so that there is no gap in deliveries when a webhook
+	// is being replaced.
+	p.Changes = append(creates, p.Changes...)
+	return &p
+}
+
+// WebhookClient is the subset of *convertkit.Client used by the Reconciler.
+type WebhookClient interface {
+	CreateWebhook(convertkit.CreateWebhookRequest) (*convertkit.CreateWebhookResponse, error)
+	DeleteWebhook(convertkit.WebhookRuleID) (*convertkit.DeleteWebhookResponse, error)
+}
+
+// Reconciler makes the webhooks registered with Convert Kit match a desired
+// list of webhooks. Use Plan to see what would change (eg for a dry run) and
+// Apply to make the changes.
+type Reconciler struct {
+	Client WebhookClient
+	// StatePath is the file used to track registered webhooks. Each
+	// environment should have its own state file.
+	StatePath string
+}
+
+// Plan loads the current state and returns the changes needed to converge it
+// with desired. Nothing is changed.
+func (rc *Reconciler) Plan(desired []Rule) (*Plan, error) {
+	for i, r := range desired {
+		err := convertkit.CreateWebhookRequest{TargetURL: r.TargetURL, Event: r.Event}.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("webhook: desired rule %d: %w", i, err)
+		}
+	}
+	state, err := LoadState(rc.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(*state, desired), nil
+}
+
+// Apply executes the plan. If the state file has changed since the plan was
+// made, eg because another Apply ran in between, nothing is done and
+// ErrStateChanged is returned so that the plan can be made and reviewed again.
+//
+// The state file is saved after every change, so if Apply fails partway
+// through the state still reflects what was done and the next Plan will pick
+// up where this one left off.
+func (rc *Reconciler) Apply(p *Plan) error {
+	state, err := LoadState(rc.StatePath)
+	if err != nil {
+		return err
+	}
+	if !sameRules(state.Rules, p.base) {
+		return ErrStateChanged
+	}
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			resp, err := rc.Client.CreateWebhook(convertkit.CreateWebhookRequest{
+				TargetURL: c.Rule.TargetURL,
+				Event:     c.Rule.Event,
+			})
+			if err != nil {
+				return fmt.Errorf("webhook: creating %s: %w", c.Rule, err)
+			}
+			state.Rules = append(state.Rules, convertkit.WebhookRule{
+				ID:        resp.Rule.ID,
+				AccountID: resp.Rule.AccountID,
+				Event:     c.Rule.Event,
+				TargetURL: c.Rule.TargetURL,
+			})
+		case ActionDelete:
+			_, err := rc.Client.DeleteWebhook(c.RuleID)
+			var ckErr convertkit.ErrorResponse
+			if err != nil && !(errors.As(err, &ckErr) && ckErr.StatusCode == http.StatusNotFound) {
+				return fmt.Errorf("webhook: deleting %s: %w", c.Rule, err)
+			}
+			state.Rules = removeRule(state.Rules, c.RuleID)
+		}
+		err = state.Save(rc.StatePath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reconcile plans and applies the changes needed to converge with desired.
+func (rc *Reconciler) Reconcile(desired []Rule) (*Plan, error) {
+	p, err := rc.Plan(desired)
+	if err != nil {
+		return nil, err
+	}
+	return p, rc.Apply(p)
+}
+
+func sameRules(a, b []convertkit.WebhookRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func removeRule(rules []convertkit.WebhookRule, id convertkit.WebhookRuleID) []convertkit.WebhookRule {
+	for i, r := range rules {
+		if r.ID == id {
+			return append(rules[:i:i], rules[i+1:]...)
+		}
+	}
+	return rules
+}
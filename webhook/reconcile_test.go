@@ -0,0 +1,150 @@
+package webhook_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+type fakeWebhookClient struct {
+	nextID  convertkit.WebhookRuleID
+	calls   []string
+	failOn  string
+	missing map[convertkit.WebhookRuleID]bool
+}
+
+func (f *fakeWebhookClient) CreateWebhook(req convertkit.CreateWebhookRequest) (*convertkit.CreateWebhookResponse, error) {
+	call := fmt.Sprintf("create %s %d", req.Event.Name, req.Event.TagID)
+	f.calls = append(f.calls, call)
+	if call == f.failOn {
+		return nil, convertkit.ErrorResponse{StatusCode: 500, Type: "server_error", Message: "boom"}
+	}
+	f.nextID++
+	return &convertkit.CreateWebhookResponse{
+		Rule: convertkit.WebhookRule{ID: f.nextID, Event: req.Event, TargetURL: req.TargetURL},
+	}, nil
+}
+
+func (f *fakeWebhookClient) DeleteWebhook(id convertkit.WebhookRuleID) (*convertkit.DeleteWebhookResponse, error) {
+	f.calls = append(f.calls, fmt.Sprintf("delete %d", id))
+	if f.missing[id] {
+		return nil, convertkit.ErrorResponse{StatusCode: http.StatusNotFound, Type: "not_found_error"}
+	}
+	return &convertkit.DeleteWebhookResponse{Success: true}, nil
+}
+
+func TestReconciler(t *testing.T) {
+	tagRule := func(id convertkit.TagID) webhook.Rule {
+		return webhook.Rule{
+			Event:     convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: id},
+			TargetURL: fmt.Sprintf("https://example.com/webhooks?tag=%d", id),
+		}
+	}
+	client := &fakeWebhookClient{nextID: 100}
+	rc := &webhook.Reconciler{
+		Client:    client,
+		StatePath: filepath.Join(t.TempDir(), "webhooks.json"),
+	}
+
+	p, err := rc.Plan([]webhook.Rule{tagRule(1), tagRule(2), tagRule(2)})
+	if err != nil {
+		t.Fatalf("Plan() err = %v; want nil", err)
+	}
+	if len(p.Changes) != 2 {
+		t.Fatalf("len(Changes) = %d; want 2\n%v", len(p.Changes), p)
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("Plan() made calls: %v", client.calls)
+	}
+	err = rc.Apply(p)
+	if err != nil {
+		t.Fatalf("Apply() err = %v; want nil", err)
+	}
+
+	// Re-planning the same rules is a no-op.
+	p, err = rc.Plan([]webhook.Rule{tagRule(2), tagRule(1)})
+	if err != nil {
+		t.Fatalf("Plan() err = %v; want nil", err)
+	}
+	if !p.Empty() {
+		t.Errorf("Plan() = %v; want no changes", p)
+	}
+
+	// Swap tag 1 for tag 3.
+	client.calls = nil
+	client.missing = map[convertkit.WebhookRuleID]bool{101: true}
+	p, err = rc.Reconcile([]webhook.Rule{tagRule(2), tagRule(3)})
+	if err != nil {
+		t.Fatalf("Reconcile() err = %v; want nil", err)
+	}
+	want := []string{"create subscriber.tag_add 3", "delete 101"}
+	if fmt.Sprint(client.calls) != fmt.Sprint(want) {
+		t.Errorf("calls = %v; want %v", client.calls, want)
+	}
+	diff := p.String()
+	for _, line := range []string{
+		"+ subscriber.tag_add tag_id=3 -> https://example.com/webhooks?tag=3",
+		"- subscriber.tag_add tag_id=1 -> https://example.com/webhooks?tag=1 (rule 101)",
+		"Plan: 1 to create, 1 to delete, 1 unchanged.",
+	} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("String() = %q; want it to contain %q", diff, line)
+		}
+	}
+	state, err := webhook.LoadState(rc.StatePath)
+	if err != nil {
+		t.Fatalf("LoadState() err = %v; want nil", err)
+	}
+	if len(state.Rules) != 2 {
+		t.Errorf("len(state.Rules) = %d; want 2", len(state.Rules))
+	}
+
+	t.Run("partial failure", func(t *testing.T) {
+		client.failOn = "create subscriber.tag_add 5"
+		_, err := rc.Reconcile([]webhook.Rule{tagRule(2), tagRule(3), tagRule(4), tagRule(5)})
+		if err == nil {
+			t.Fatalf("Reconcile() err = nil; want error")
+		}
+		client.failOn = ""
+		p, err := rc.Plan([]webhook.Rule{tagRule(2), tagRule(3), tagRule(4), tagRule(5)})
+		if err != nil {
+			t.Fatalf("Plan() err = %v; want nil", err)
+		}
+		if len(p.Changes) != 1 || p.Changes[0].Rule != tagRule(5) {
+			t.Errorf("Plan() = %v; want only tag 5 to be created", p)
+		}
+	})
+
+	t.Run("stale plan", func(t *testing.T) {
+		desired := []webhook.Rule{tagRule(2), tagRule(3), tagRule(4), tagRule(5), tagRule(6)}
+		p, err := rc.Plan(desired)
+		if err != nil {
+			t.Fatalf("Plan() err = %v; want nil", err)
+		}
+		_, err = rc.Reconcile(desired[:4])
+		if err != nil {
+			t.Fatalf("Reconcile() err = %v; want nil", err)
+		}
+		client.calls = nil
+		err = rc.Apply(p)
+		if !errors.Is(err, webhook.ErrStateChanged) {
+			t.Errorf("Apply() err = %v; want %v", err, webhook.ErrStateChanged)
+		}
+		if len(client.calls) != 0 {
+			t.Errorf("Apply() made calls: %v", client.calls)
+		}
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := rc.Plan([]webhook.Rule{{Event: convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd}}})
+		if err == nil {
+			t.Errorf("Plan() err = nil; want error")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record is a single webhook delivery as it was received.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// URL is the request URI, ie the path and query the delivery was sent to.
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// Request returns an *http.Request for the record. If base is not empty, the
+// record's path and query are resolved against it.
+func (rec Record) Request(base string) (*http.Request, error) {
+	target := rec.URL
+	if base != "" {
+		target = base + rec.URL
+	}
+	method := rec.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	r, err := http.NewRequest(method, target, bytes.NewReader([]byte(rec.Body)))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: building request: %w", err)
+	}
+	for k, vs := range rec.Header {
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+	if base == "" && !r.URL.IsAbs() {
+		// Allow the request to be passed directly to an http.Handler.
+		r.RequestURI = rec.URL
+	}
+	return r, nil
+}
+
+// Recorder is middleware that appends every webhook delivery to a JSONL
+// archive so that it can be replayed later with a Replayer.
+//
+// Records include the full URL, so they contain any verification tokens. Keep
+// archives somewhere safe.
+type Recorder struct {
+	// W is where records are written, one JSON object per line.
+	W io.Writer
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// OnError, if set, is called when a record can't be written. Recording
+	// errors never affect the response.
+	OnError func(r *http.Request, err error)
+
+	mu sync.Mutex
+}
+
+// Middleware returns an http.Handler that records each request before passing
+// it to next.
+func (rc *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := rc.record(r)
+		if err != nil && rc.OnError != nil {
+			rc.OnError(r, err)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (rc *Recorder) record(r *http.Request) error {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, MaxBodySize))
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: reading body: %w", err)
+	}
+	now := time.Now
+	if rc.Now != nil {
+		now = rc.Now
+	}
+	b, err := json.Marshal(Record{
+		Time:   now().UTC(),
+		Method: r.Method,
+		URL:    r.URL.RequestURI(),
+		Header: r.Header.Clone(),
+		Body:   string(body),
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: encoding record: %w", err)
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	_, err = rc.W.Write(append(b, '\n'))
+	if err != nil {
+		return fmt.Errorf("webhook: writing record: %w", err)
+	}
+	return nil
+}
+
+// ReadRecords reads every record from a JSONL archive written by a Recorder.
+func ReadRecords(r io.Reader) ([]Record, error) {
+	var ret []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 2*MaxBodySize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var rec Record
+		err := json.Unmarshal(b, &rec)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: decoding record on line %d: %w", line, err)
+		}
+		ret = append(ret, rec)
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("webhook: reading records: %w", err)
+	}
+	return ret, nil
+}
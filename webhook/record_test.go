@@ -0,0 +1,79 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func TestRecorder(t *testing.T) {
+	var archive bytes.Buffer
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	rec := &webhook.Recorder{
+		W:   &archive,
+		Now: func() time.Time { return now },
+	}
+	var gotBody []byte
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := rec.Middleware(next)
+	tagAdd := convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14}
+	body := fixture(t, "subscriber")
+	r := deliveryRequest(t, tagAdd, body)
+	r.Header.Set("User-Agent", "ConvertKit-Webhook")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	now = now.Add(time.Minute)
+	handler.ServeHTTP(httptest.NewRecorder(), deliveryRequest(t, convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberActivate}, body))
+
+	if !bytes.Equal(gotBody, body) {
+		t.Errorf("next received body %q; want %q", gotBody, body)
+	}
+	records, err := webhook.ReadRecords(&archive)
+	if err != nil {
+		t.Fatalf("ReadRecords() err = %v; want nil", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d; want 2", len(records))
+	}
+	first := records[0]
+	if first.URL != "/webhooks?event=subscriber.tag_add&tag_id=14" {
+		t.Errorf("URL = %v; want %v", first.URL, "/webhooks?event=subscriber.tag_add&tag_id=14")
+	}
+	if first.Body != string(body) {
+		t.Errorf("Body = %q; want %q", first.Body, body)
+	}
+	if got := first.Header.Get("User-Agent"); got != "ConvertKit-Webhook" {
+		t.Errorf("User-Agent = %v; want ConvertKit-Webhook", got)
+	}
+	if !first.Time.Equal(now.Add(-time.Minute)) {
+		t.Errorf("Time = %v; want %v", first.Time, now.Add(-time.Minute))
+	}
+
+	t.Run("replay to handler", func(t *testing.T) {
+		var h webhook.Handler
+		var got webhook.TagAddEvent
+		h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+			got = e
+			return nil
+		})
+		var rp webhook.Replayer
+		results := rp.ReplayTo(&h, records)
+		for _, res := range results {
+			if res.Err != nil || res.StatusCode != http.StatusNoContent {
+				t.Errorf("result = %d, %v; want %d, nil", res.StatusCode, res.Err, http.StatusNoContent)
+			}
+		}
+		if got.Subscriber.Email != "jonsnow@example.com" {
+			t.Errorf("Subscriber.Email = %v; want jonsnow@example.com", got.Subscriber.Email)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// ReplayResult is the outcome of replaying a single Record.
+type ReplayResult struct {
+	Record     Record
+	StatusCode int
+	Err        error
+}
+
+// Replayer re-sends recorded webhook deliveries, eg to reproduce an incident
+// locally. Records can be filtered by event type and time range, and IDs can
+// be rewritten so that deliveries line up with local test data.
+type Replayer struct {
+	// Events, if not empty, limits replays to these events.
+	Events []convertkit.WebhookEventName
+	// From and To, if not zero, limit replays to records received within the
+	// time range. From is inclusive and To is exclusive.
+	From, To time.Time
+
+	// SubscriberIDs rewrites the subscriber's ID in the body of each record.
+	// The other maps rewrite the form_id, sequence_id and tag_id query
+	// parameters in its URL, which is the only place those IDs appear.
+	// Purchase bodies don't reference any of these IDs and are left as is.
+	SubscriberIDs map[convertkit.SubscriberID]convertkit.SubscriberID
+	FormIDs       map[convertkit.FormID]convertkit.FormID
+	SequenceIDs   map[convertkit.SequenceID]convertkit.SequenceID
+	TagIDs        map[convertkit.TagID]convertkit.TagID
+	// Rewrite, if set, is called on each record after IDs are rewritten.
+	Rewrite func(*Record) error
+
+	// Verifier, if set, is used to re-sign each record's URL. This is needed
+	// when URL IDs are rewritten or when replaying to an environment with
+	// different secrets.
+	Verifier *Verifier
+
+	// HTTPClient is used by ReplayURL. Defaults to http.DefaultClient.
+	HTTPClient interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+}
+
+// Filter returns the records that match the Replayer's filters.
+func (rp *Replayer) Filter(records []Record) []Record {
+	var ret []Record
+	for _, rec := range records {
+		if !rp.From.IsZero() && rec.Time.Before(rp.From) {
+			continue
+		}
+		if !rp.To.IsZero() && !rec.Time.Before(rp.To) {
+			continue
+		}
+		if len(rp.Events) > 0 && !rp.matchesEvent(rec) {
+			continue
+		}
+		ret = append(ret, rec)
+	}
+	return ret
+}
+
+func (rp *Replayer) matchesEvent(rec Record) bool {
+	u, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+	name := convertkit.WebhookEventName(u.Query().Get(ParamEvent))
+	for _, e := range rp.Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepare applies the Replayer's rewrites and re-signing to a record.
+func (rp *Replayer) Prepare(rec Record) (Record, error) {
+	var err error
+	rec.Body, err = rp.rewriteBody(rec.Body)
+	if err != nil {
+		return rec, err
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil {
+		return rec, fmt.Errorf("webhook: parsing record url: %w", err)
+	}
+	query := u.Query()
+	rewriteParam(query, ParamFormID, func(id int) int { return int(rp.FormIDs[convertkit.FormID(id)]) })
+	rewriteParam(query, ParamSequenceID, func(id int) int { return int(rp.SequenceIDs[convertkit.SequenceID(id)]) })
+	rewriteParam(query, ParamTagID, func(id int) int { return int(rp.TagIDs[convertkit.TagID(id)]) })
+	u.RawQuery = query.Encode()
+	rec.URL = u.RequestURI()
+	if rp.Rewrite != nil {
+		err = rp.Rewrite(&rec)
+		if err != nil {
+			return rec, err
+		}
+	}
+	if rp.Verifier != nil {
+		rec.URL, err = rp.Verifier.Sign(rec.URL)
+		if err != nil {
+			return rec, err
+		}
+	}
+	return rec, nil
+}
+
+func rewriteParam(query url.Values, key string, lookup func(int) int) {
+	id, err := strconv.Atoi(query.Get(key))
+	if err != nil {
+		return
+	}
+	if newID := lookup(id); newID != 0 {
+		query.Set(key, strconv.Itoa(newID))
+	}
+}
+
+func (rp *Replayer) rewriteBody(body string) (string, error) {
+	if len(rp.SubscriberIDs) == 0 {
+		return body, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(body)))
+	dec.UseNumber()
+	var payload map[string]interface{}
+	err := dec.Decode(&payload)
+	if err != nil {
+		return body, fmt.Errorf("webhook: decoding record body: %w", err)
+	}
+	sub, ok := payload["subscriber"].(map[string]interface{})
+	if !ok {
+		return body, nil
+	}
+	n, ok := sub["id"].(json.Number)
+	if !ok {
+		return body, nil
+	}
+	id, err := strconv.Atoi(n.String())
+	if err != nil {
+		return body, nil
+	}
+	newID, ok := rp.SubscriberIDs[convertkit.SubscriberID(id)]
+	if !ok {
+		return body, nil
+	}
+	sub["id"] = json.Number(strconv.Itoa(int(newID)))
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return body, fmt.Errorf("webhook: encoding record body: %w", err)
+	}
+	return string(b), nil
+}
+
+// ReplayTo sends the matching records to h, in order.
+func (rp *Replayer) ReplayTo(h http.Handler, records []Record) []ReplayResult {
+	var ret []ReplayResult
+	for _, rec := range rp.Filter(records) {
+		res := ReplayResult{Record: rec}
+		rec, res.Err = rp.Prepare(rec)
+		if res.Err == nil {
+			var r *http.Request
+			r, res.Err = rec.Request("")
+			if res.Err == nil {
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				res.StatusCode = w.Code
+			}
+		}
+		res.Record = rec
+		ret = append(ret, res)
+	}
+	return ret
+}
+
+// ReplayURL sends the matching records to base, in order. Each record's path
+// and query are appended to base, so base should usually just be a scheme
+// and host, eg "http://localhost:3000".
+func (rp *Replayer) ReplayURL(base string, records []Record) []ReplayResult {
+	httpClient := rp.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	var ret []ReplayResult
+	for _, rec := range rp.Filter(records) {
+		res := ReplayResult{Record: rec}
+		rec, res.Err = rp.Prepare(rec)
+		if res.Err == nil {
+			res.StatusCode, res.Err = post(httpClient, base, rec)
+		}
+		res.Record = rec
+		ret = append(ret, res)
+	}
+	return ret
+}
+
+func post(httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}, base string, rec Record) (int, error) {
+	r, err := rec.Request(base)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
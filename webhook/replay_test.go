@@ -0,0 +1,142 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func replayRecords(t *testing.T) []webhook.Record {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	body := string(fixture(t, "subscriber"))
+	return []webhook.Record{
+		{Time: start, Method: http.MethodPost, URL: "/webhooks?event=subscriber.tag_add&tag_id=14", Body: body},
+		{Time: start.Add(time.Hour), Method: http.MethodPost, URL: "/webhooks?event=subscriber.subscriber_activate", Body: body},
+		{Time: start.Add(2 * time.Hour), Method: http.MethodPost, URL: "/webhooks?event=subscriber.tag_add&tag_id=15", Body: body},
+	}
+}
+
+func TestReplayer_Filter(t *testing.T) {
+	records := replayRecords(t)
+	rp := webhook.Replayer{
+		Events: []convertkit.WebhookEventName{convertkit.WebhookTagAdd},
+		From:   records[0].Time.Add(time.Minute),
+	}
+	got := rp.Filter(records)
+	if len(got) != 1 || got[0].URL != records[2].URL {
+		t.Errorf("Filter() = %+v; want only the last record", got)
+	}
+	rp = webhook.Replayer{To: records[1].Time}
+	got = rp.Filter(records)
+	if len(got) != 1 || got[0].URL != records[0].URL {
+		t.Errorf("Filter() = %+v; want only the first record", got)
+	}
+}
+
+func TestReplayer_Prepare(t *testing.T) {
+	subscriber := string(fixture(t, "subscriber"))
+	purchase := string(fixture(t, "purchase"))
+	tests := map[string]struct {
+		rp       webhook.Replayer
+		url      string
+		body     string
+		wantURL  string
+		wantBody string
+	}{
+		"subscriber": {
+			rp:       webhook.Replayer{SubscriberIDs: map[convertkit.SubscriberID]convertkit.SubscriberID{1: 1001}},
+			url:      "/webhooks?event=subscriber.subscriber_activate",
+			body:     subscriber,
+			wantURL:  "/webhooks?event=subscriber.subscriber_activate",
+			wantBody: `"id":1001`,
+		},
+		"form": {
+			rp:       webhook.Replayer{FormIDs: map[convertkit.FormID]convertkit.FormID{213: 1213}},
+			url:      "/webhooks?event=subscriber.form_subscribe&form_id=213",
+			body:     subscriber,
+			wantURL:  "/webhooks?event=subscriber.form_subscribe&form_id=1213",
+			wantBody: `"id": 1,`,
+		},
+		"sequence": {
+			rp:       webhook.Replayer{SequenceIDs: map[convertkit.SequenceID]convertkit.SequenceID{55: 1055}},
+			url:      "/webhooks?event=subscriber.course_subscribe&sequence_id=55",
+			body:     subscriber,
+			wantURL:  "/webhooks?event=subscriber.course_subscribe&sequence_id=1055",
+			wantBody: `"id": 1,`,
+		},
+		"tag": {
+			rp:       webhook.Replayer{TagIDs: map[convertkit.TagID]convertkit.TagID{14: 1014}},
+			url:      "/webhooks?event=subscriber.tag_add&tag_id=14",
+			body:     subscriber,
+			wantURL:  "/webhooks?event=subscriber.tag_add&tag_id=1014",
+			wantBody: `"id": 1,`,
+		},
+		"purchase": {
+			rp:       webhook.Replayer{SubscriberIDs: map[convertkit.SubscriberID]convertkit.SubscriberID{1: 1001}},
+			url:      "/webhooks?event=purchase.purchase_create",
+			body:     purchase,
+			wantURL:  "/webhooks?event=purchase.purchase_create",
+			wantBody: purchase,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.rp.Prepare(webhook.Record{Method: http.MethodPost, URL: tc.url, Body: tc.body})
+			if err != nil {
+				t.Fatalf("Prepare() err = %v; want nil", err)
+			}
+			if got.URL != tc.wantURL {
+				t.Errorf("URL = %q; want %q", got.URL, tc.wantURL)
+			}
+			if !strings.Contains(got.Body, tc.wantBody) {
+				t.Errorf("Body = %s; want it to contain %s", got.Body, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestReplayer_rewrite(t *testing.T) {
+	v := &webhook.Verifier{Secrets: [][]byte{[]byte("local-secret")}}
+	var got []webhook.TagAddEvent
+	var h webhook.Handler
+	h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+		got = append(got, e)
+		return nil
+	})
+	server := httptest.NewServer(v.Middleware(&h))
+	defer server.Close()
+
+	rp := webhook.Replayer{
+		Events:        []convertkit.WebhookEventName{convertkit.WebhookTagAdd},
+		SubscriberIDs: map[convertkit.SubscriberID]convertkit.SubscriberID{1: 1001},
+		TagIDs:        map[convertkit.TagID]convertkit.TagID{14: 1014},
+		Verifier:      v,
+	}
+	results := rp.ReplayURL(server.URL, replayRecords(t))
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d; want 2", len(results))
+	}
+	for _, res := range results {
+		if res.Err != nil || res.StatusCode != http.StatusNoContent {
+			t.Errorf("result = %d, %v; want %d, nil", res.StatusCode, res.Err, http.StatusNoContent)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(events) = %d; want 2", len(got))
+	}
+	if got[0].Subscriber.ID != 1001 || got[0].Tag.ID != 1014 {
+		t.Errorf("event[0] = %d, %d; want 1001, 1014", got[0].Subscriber.ID, got[0].Tag.ID)
+	}
+	if got[0].Subscriber.Fields["last_name"] != "Snow" {
+		t.Errorf("rewriting dropped fields: %+v", got[0].Subscriber)
+	}
+	if got[1].Tag.ID != 15 {
+		t.Errorf("event[1].Tag.ID = %d; want 15", got[1].Tag.ID)
+	}
+}
@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// Simulator builds realistic webhook deliveries and sends them to a handler
+// or URL. It is meant for exercising handlers in tests and local development,
+// where Convert Kit can't reach your server.
+//
+// Each method takes the resources involved in the event. Any zero fields are
+// filled in with realistic defaults, so the values provided act as overrides,
+// eg:
+//
+//	sim := &webhook.Simulator{Handler: &h}
+//	sim.TagAdd(convertkit.Subscriber{Email: "jon@example.com"}, convertkit.Tag{ID: 14})
+type Simulator struct {
+	// Handler receives deliveries directly. Takes precedence over URL.
+	Handler http.Handler
+	// URL is the base URL deliveries are POSTed to, eg
+	// "http://localhost:3000/webhooks/convertkit".
+	URL string
+	// Verifier, if set, signs each delivery's URL.
+	Verifier *Verifier
+	// Now returns the current time used for timestamps. Defaults to time.Now.
+	Now func() time.Time
+	// HTTPClient is used when sending to URL. Defaults to http.DefaultClient.
+	HTTPClient interface {
+		Do(*http.Request) (*http.Response, error)
+	}
+}
+
+func (s *Simulator) now() time.Time {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	return now().UTC().Truncate(time.Second)
+}
+
+// Subscriber returns sub with any zero fields filled in with defaults.
+func (s *Simulator) Subscriber(sub convertkit.Subscriber, state string) convertkit.Subscriber {
+	if sub.ID == 0 {
+		sub.ID = 1
+	}
+	if sub.FirstName == "" {
+		sub.FirstName = "Jon"
+	}
+	if sub.Email == "" {
+		sub.Email = fmt.Sprintf("subscriber%d@example.com", sub.ID)
+	}
+	if sub.State == "" {
+		sub.State = state
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = s.now()
+	}
+	if sub.Fields == nil {
+		sub.Fields = make(map[string]string)
+	}
+	return sub
+}
+
+// Purchase returns p with any zero fields filled in with defaults. Totals are
+// computed from the products when they aren't provided.
+func (s *Simulator) Purchase(p convertkit.Purchase) convertkit.Purchase {
+	if p.ID == 0 {
+		p.ID = 1
+	}
+	if p.TransactionID == "" {
+		p.TransactionID = fmt.Sprintf("txn-%d", p.ID)
+	}
+	if p.Status == "" {
+		p.Status = "paid"
+	}
+	if p.Email == "" {
+		p.Email = "subscriber1@example.com"
+	}
+	if p.Currency == "" {
+		p.Currency = "USD"
+	}
+	if p.TransactionTime.IsZero() {
+		p.TransactionTime = s.now()
+	}
+	if len(p.Products) == 0 {
+		p.Products = []convertkit.PurchaseProduct{{
+			UnitPrice: 10,
+			Quantity:  1,
+			SKU:       "sku-1",
+			PID:       1,
+			LID:       1,
+			Name:      "Example Product",
+		}}
+	}
+	if p.Subtotal == 0 {
+		for _, prod := range p.Products {
+			p.Subtotal += prod.UnitPrice * float64(prod.Quantity)
+		}
+	}
+	if p.Total == 0 {
+		p.Total = p.Subtotal + p.Shipping + p.Tax - p.Discount
+	}
+	return p
+}
+
+// Send delivers a webhook for event with the given payload and returns the
+// HTTP status code of the response.
+func (s *Simulator) Send(event convertkit.WebhookEvent, p Payload) (int, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: encoding payload: %w", err)
+	}
+	base := s.URL
+	if s.Handler != nil || base == "" {
+		base = "/"
+	}
+	target, err := TargetURL(base, event)
+	if err != nil {
+		return 0, err
+	}
+	if s.Verifier != nil {
+		target, err = s.Verifier.Sign(target)
+		if err != nil {
+			return 0, err
+		}
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return 0, fmt.Errorf("webhook: parsing target url: %w", err)
+	}
+	rec := Record{
+		Time:   s.now(),
+		Method: http.MethodPost,
+		URL:    u.RequestURI(),
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   string(body),
+	}
+	if s.Handler != nil {
+		r, err := rec.Request("")
+		if err != nil {
+			return 0, err
+		}
+		w := httptest.NewRecorder()
+		s.Handler.ServeHTTP(w, r)
+		return w.Code, nil
+	}
+	if s.URL == "" {
+		return 0, fmt.Errorf("webhook: simulator needs a Handler or URL")
+	}
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return post(httpClient, u.Scheme+"://"+u.Host, rec)
+}
+
+func (s *Simulator) sendSubscriber(event convertkit.WebhookEvent, sub convertkit.Subscriber, state string) (int, error) {
+	sub = s.Subscriber(sub, state)
+	return s.Send(event, Payload{Subscriber: &sub})
+}
+
+// SubscriberActivate sends a subscriber activate event.
+func (s *Simulator) SubscriberActivate(sub convertkit.Subscriber) (int, error) {
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberActivate}, sub, "active")
+}
+
+// SubscriberUnsubscribe sends a subscriber unsubscribe event.
+func (s *Simulator) SubscriberUnsubscribe(sub convertkit.Subscriber) (int, error) {
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberUnsubscribe}, sub, "cancelled")
+}
+
+// SubscriberBounce sends a subscriber bounce event.
+func (s *Simulator) SubscriberBounce(sub convertkit.Subscriber) (int, error) {
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberBounce}, sub, "bounced")
+}
+
+// SubscriberComplain sends a subscriber complain event.
+func (s *Simulator) SubscriberComplain(sub convertkit.Subscriber) (int, error) {
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberComplain}, sub, "complained")
+}
+
+// FormSubscribe sends a form subscribe event.
+func (s *Simulator) FormSubscribe(sub convertkit.Subscriber, form convertkit.Form) (int, error) {
+	if form.ID == 0 {
+		form.ID = 1
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookFormSubscribe, FormID: form.ID}, sub, "active")
+}
+
+// SequenceSubscribe sends a sequence subscribe event.
+func (s *Simulator) SequenceSubscribe(sub convertkit.Subscriber, seq convertkit.Sequence) (int, error) {
+	if seq.ID == 0 {
+		seq.ID = 1
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookSequenceSubscribe, SequenceID: seq.ID}, sub, "active")
+}
+
+// SequenceComplete sends a sequence complete event.
+func (s *Simulator) SequenceComplete(sub convertkit.Subscriber, seq convertkit.Sequence) (int, error) {
+	if seq.ID == 0 {
+		seq.ID = 1
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookSequenceComplete, SequenceID: seq.ID}, sub, "active")
+}
+
+// TagAdd sends a tag add event.
+func (s *Simulator) TagAdd(sub convertkit.Subscriber, tag convertkit.Tag) (int, error) {
+	if tag.ID == 0 {
+		tag.ID = 1
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: tag.ID}, sub, "active")
+}
+
+// TagRemove sends a tag remove event.
+func (s *Simulator) TagRemove(sub convertkit.Subscriber, tag convertkit.Tag) (int, error) {
+	if tag.ID == 0 {
+		tag.ID = 1
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookTagRemove, TagID: tag.ID}, sub, "active")
+}
+
+// LinkClick sends a link click event. link defaults to
+// "https://example.com".
+func (s *Simulator) LinkClick(sub convertkit.Subscriber, link string) (int, error) {
+	if link == "" {
+		link = "https://example.com"
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookLinkClick, InitiatorValue: link}, sub, "active")
+}
+
+// ProductPurchase sends a product purchase event. productID defaults to 1.
+func (s *Simulator) ProductPurchase(sub convertkit.Subscriber, productID int) (int, error) {
+	if productID == 0 {
+		productID = 1
+	}
+	return s.sendSubscriber(convertkit.WebhookEvent{Name: convertkit.WebhookProductPurchase, ProductID: productID}, sub, "active")
+}
+
+// PurchaseCreate sends a purchase create event.
+func (s *Simulator) PurchaseCreate(p convertkit.Purchase) (int, error) {
+	p = s.Purchase(p)
+	return s.Send(convertkit.WebhookEvent{Name: convertkit.WebhookPurchaseCreate}, Payload{Purchase: &p})
+}
@@ -0,0 +1,129 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func TestSimulator(t *testing.T) {
+	var got []webhook.Event
+	var h webhook.Handler
+	for _, name := range []convertkit.WebhookEventName{
+		convertkit.WebhookSubscriberActivate,
+		convertkit.WebhookSubscriberUnsubscribe,
+		convertkit.WebhookSubscriberBounce,
+		convertkit.WebhookSubscriberComplain,
+		convertkit.WebhookFormSubscribe,
+		convertkit.WebhookSequenceSubscribe,
+		convertkit.WebhookSequenceComplete,
+		convertkit.WebhookTagAdd,
+		convertkit.WebhookTagRemove,
+		convertkit.WebhookLinkClick,
+		convertkit.WebhookProductPurchase,
+		convertkit.WebhookPurchaseCreate,
+	} {
+		h.Handle(name, func(ctx context.Context, e webhook.Event) error {
+			got = append(got, e)
+			return nil
+		})
+	}
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	sim := &webhook.Simulator{
+		Handler: &h,
+		Now:     func() time.Time { return now },
+	}
+	sub := convertkit.Subscriber{ID: 7, Email: "jonsnow@example.com"}
+	for _, send := range []func() (int, error){
+		func() (int, error) { return sim.SubscriberActivate(sub) },
+		func() (int, error) { return sim.SubscriberUnsubscribe(sub) },
+		func() (int, error) { return sim.SubscriberBounce(sub) },
+		func() (int, error) { return sim.SubscriberComplain(sub) },
+		func() (int, error) { return sim.FormSubscribe(sub, convertkit.Form{ID: 213}) },
+		func() (int, error) { return sim.SequenceSubscribe(sub, convertkit.Sequence{ID: 55}) },
+		func() (int, error) { return sim.SequenceComplete(sub, convertkit.Sequence{}) },
+		func() (int, error) { return sim.TagAdd(sub, convertkit.Tag{ID: 14}) },
+		func() (int, error) { return sim.TagRemove(sub, convertkit.Tag{ID: 14}) },
+		func() (int, error) { return sim.LinkClick(sub, "https://example.com/course") },
+		func() (int, error) { return sim.ProductPurchase(sub, 9999) },
+		func() (int, error) {
+			return sim.PurchaseCreate(convertkit.Purchase{Products: []convertkit.PurchaseProduct{
+				{UnitPrice: 5, Quantity: 2},
+			}})
+		},
+	} {
+		status, err := send()
+		if err != nil || status != http.StatusNoContent {
+			t.Errorf("send = %d, %v; want %d, nil", status, err, http.StatusNoContent)
+		}
+	}
+	if len(got) != 12 {
+		t.Fatalf("len(events) = %d; want 12", len(got))
+	}
+
+	wantSub := convertkit.Subscriber{
+		ID:        7,
+		FirstName: "Jon",
+		Email:     "jonsnow@example.com",
+		State:     "active",
+		CreatedAt: now,
+		Fields:    map[string]string{},
+	}
+	if e := got[0].(webhook.SubscriberActivateEvent); !reflect.DeepEqual(e.Subscriber, wantSub) {
+		t.Errorf("Subscriber = %+v; want %+v", e.Subscriber, wantSub)
+	}
+	if e := got[1].(webhook.SubscriberUnsubscribeEvent); e.Subscriber.State != "cancelled" {
+		t.Errorf("unsubscribe State = %v; want cancelled", e.Subscriber.State)
+	}
+	if e := got[4].(webhook.FormSubscribeEvent); e.Form.ID != 213 {
+		t.Errorf("Form.ID = %v; want 213", e.Form.ID)
+	}
+	if e := got[6].(webhook.SequenceCompleteEvent); e.Sequence.ID != 1 {
+		t.Errorf("default Sequence.ID = %v; want 1", e.Sequence.ID)
+	}
+	if e := got[9].(webhook.LinkClickEvent); e.URL != "https://example.com/course" {
+		t.Errorf("URL = %v; want https://example.com/course", e.URL)
+	}
+	pc := got[11].(webhook.PurchaseCreateEvent)
+	if pc.Purchase.Total != 10 || pc.Purchase.Currency != "USD" {
+		t.Errorf("Purchase = %+v; want Total 10 USD", pc.Purchase)
+	}
+}
+
+func TestSimulator_URL(t *testing.T) {
+	v := &webhook.Verifier{Secrets: [][]byte{[]byte("secret")}}
+	var got webhook.TagAddEvent
+	var h webhook.Handler
+	h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+		got = e
+		return nil
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/convertkit", v.Middleware(&h))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	sim := &webhook.Simulator{
+		URL:      server.URL + "/webhooks/convertkit",
+		Verifier: v,
+	}
+	status, err := sim.TagAdd(convertkit.Subscriber{}, convertkit.Tag{ID: 14})
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("TagAdd() = %d, %v; want %d, nil", status, err, http.StatusNoContent)
+	}
+	if got.Tag.ID != 14 || got.Subscriber.Email != "subscriber1@example.com" {
+		t.Errorf("event = %+v; want tag 14 for subscriber1@example.com", got)
+	}
+
+	sim.Verifier = nil
+	status, _ = sim.TagAdd(convertkit.Subscriber{}, convertkit.Tag{ID: 14})
+	if status != http.StatusUnauthorized {
+		t.Errorf("unsigned TagAdd() = %d; want %d", status, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joncalhoun/convertkit/internal/atomicfile"
+)
+
+// SeenStore keeps track of the webhook deliveries that have already been
+// processed. Implementations must be safe for concurrent use.
+type SeenStore interface {
+	// Seen reports whether key has been claimed and hasn't expired.
+	Seen(key string) (bool, error)
+	// Claim marks key as seen for ttl. It reports whether key, or any of the
+	// keys in prev, was already seen, in which case nothing is claimed.
+	// Checking and claiming must be atomic so that concurrent deliveries of
+	// the same event can't both be processed.
+	Claim(key string, ttl time.Duration, prev ...string) (alreadySeen bool, err error)
+	// Release removes key so that the delivery can be retried.
+	Release(key string) error
+}
+
+// pruneEvery is the number of claims between scans for expired keys.
+const pruneEvery = 100
+
+// seenKeys is the state shared by MemoryStore and FileStore. Callers must
+// hold the store's lock.
+type seenKeys struct {
+	expires map[string]time.Time
+	claims  int
+}
+
+func (sk *seenKeys) seen(now time.Time, keys ...string) bool {
+	for _, key := range keys {
+		if exp, ok := sk.expires[key]; ok && now.Before(exp) {
+			return true
+		}
+	}
+	return false
+}
+
+// claim claims key and reports whether expired keys were pruned.
+func (sk *seenKeys) claim(now time.Time, key string, ttl time.Duration) bool {
+	if sk.expires == nil {
+		sk.expires = make(map[string]time.Time)
+	}
+	sk.expires[key] = now.Add(ttl)
+	sk.claims++
+	if sk.claims%pruneEvery != 0 {
+		return false
+	}
+	for k, exp := range sk.expires {
+		if !now.Before(exp) {
+			delete(sk.expires, k)
+		}
+	}
+	return true
+}
+
+// MemoryStore is an in-memory SeenStore. The zero value is ready to use.
+type MemoryStore struct {
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu   sync.Mutex
+	keys seenKeys
+}
+
+func (ms *MemoryStore) now() time.Time {
+	if ms.Now != nil {
+		return ms.Now()
+	}
+	return time.Now()
+}
+
+// Seen implements SeenStore.
+func (ms *MemoryStore) Seen(key string) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.keys.seen(ms.now(), key), nil
+}
+
+// Claim implements SeenStore.
+func (ms *MemoryStore) Claim(key string, ttl time.Duration, prev ...string) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := ms.now()
+	if ms.keys.seen(now, key) || ms.keys.seen(now, prev...) {
+		return true, nil
+	}
+	ms.keys.claim(now, key, ttl)
+	return false, nil
+}
+
+// Release implements SeenStore.
+func (ms *MemoryStore) Release(key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.keys.expires, key)
+	return nil
+}
+
+// FileStore is a SeenStore that persists seen keys to a file so that they
+// survive restarts. Claims and releases are appended to the file, which is
+// rewritten without expired keys whenever they are pruned. It is only safe for
+// use by a single process.
+type FileStore struct {
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	path string
+	mu   sync.Mutex
+	keys seenKeys
+}
+
+// fileStoreEntry is a single line of a FileStore's file. A zero Expires
+// releases the key.
+type fileStoreEntry struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// NewFileStore opens the FileStore at path, creating it if it doesn't exist.
+func NewFileStore(path string) (*FileStore, error) {
+	fs := &FileStore{
+		path: path,
+		keys: seenKeys{expires: make(map[string]time.Time)},
+	}
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return fs, nil
+	case err != nil:
+		return nil, fmt.Errorf("webhook: reading seen store: %w", err)
+	}
+	lines := bytes.Split(b, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry fileStoreEntry
+		err = json.Unmarshal(line, &entry)
+		if err != nil {
+			if i == len(lines)-1 {
+				// A crash while appending can leave a partial last line.
+				// Its claim was never reported, so it is safe to drop.
+				break
+			}
+			return nil, fmt.Errorf("webhook: decoding seen store: %w", err)
+		}
+		if entry.Expires.IsZero() {
+			delete(fs.keys.expires, entry.Key)
+			continue
+		}
+		fs.keys.expires[entry.Key] = entry.Expires
+	}
+	return fs, nil
+}
+
+func (fs *FileStore) now() time.Time {
+	if fs.Now != nil {
+		return fs.Now()
+	}
+	return time.Now()
+}
+
+// Seen implements SeenStore.
+func (fs *FileStore) Seen(key string) (bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.keys.seen(fs.now(), key), nil
+}
+
+// Claim implements SeenStore.
+func (fs *FileStore) Claim(key string, ttl time.Duration, prev ...string) (bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	now := fs.now()
+	if fs.keys.seen(now, key) || fs.keys.seen(now, prev...) {
+		return true, nil
+	}
+	var err error
+	if fs.keys.claim(now, key, ttl) {
+		err = fs.compact()
+	} else {
+		err = fs.append(fileStoreEntry{Key: key, Expires: fs.keys.expires[key]})
+	}
+	if err != nil {
+		// The claim wasn't saved, so drop it rather than treating the
+		// delivery's retry as a duplicate.
+		delete(fs.keys.expires, key)
+		return false, err
+	}
+	return false, nil
+}
+
+// Release implements SeenStore.
+func (fs *FileStore) Release(key string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, ok := fs.keys.expires[key]; !ok {
+		return nil
+	}
+	delete(fs.keys.expires, key)
+	return fs.append(fileStoreEntry{Key: key})
+}
+
+// append adds a single entry to the end of the file.
+func (fs *FileStore) append(entry fileStoreEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("webhook: encoding seen store: %w", err)
+	}
+	f, err := os.OpenFile(fs.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("webhook: saving seen store: %w", err)
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("webhook: saving seen store: %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("webhook: saving seen store: %w", err)
+	}
+	return nil
+}
+
+// compact rewrites the file with only the keys that haven't expired.
+func (fs *FileStore) compact() error {
+	err := atomicfile.Write(fs.path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		for key, exp := range fs.keys.expires {
+			err := enc.Encode(fileStoreEntry{Key: key, Expires: exp})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: saving seen store: %w", err)
+	}
+	return nil
+}
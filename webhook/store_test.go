@@ -0,0 +1,143 @@
+package webhook_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func testSeenStore(t *testing.T, store webhook.SeenStore, advance func(time.Duration)) {
+	seen, err := store.Claim("a", time.Minute)
+	if err != nil || seen {
+		t.Fatalf("Claim(a) = %v, %v; want false, nil", seen, err)
+	}
+	seen, err = store.Claim("a", time.Minute)
+	if err != nil || !seen {
+		t.Fatalf("Claim(a) again = %v, %v; want true, nil", seen, err)
+	}
+	seen, err = store.Seen("a")
+	if err != nil || !seen {
+		t.Fatalf("Seen(a) = %v, %v; want true, nil", seen, err)
+	}
+	err = store.Release("a")
+	if err != nil {
+		t.Fatalf("Release(a) err = %v; want nil", err)
+	}
+	seen, _ = store.Seen("a")
+	if seen {
+		t.Errorf("Seen(a) after Release = true; want false")
+	}
+	seen, err = store.Claim("d", time.Minute, "e", "a2")
+	if err != nil || seen {
+		t.Fatalf("Claim(d, prev e, a2) = %v, %v; want false, nil", seen, err)
+	}
+	seen, err = store.Claim("f", time.Minute, "d")
+	if err != nil || !seen {
+		t.Fatalf("Claim(f, prev d) = %v, %v; want true, nil", seen, err)
+	}
+	seen, _ = store.Seen("f")
+	if seen {
+		t.Errorf("Seen(f) after a duplicate Claim = true; want false")
+	}
+	store.Claim("b", time.Minute)
+	advance(2 * time.Minute)
+	seen, _ = store.Seen("b")
+	if seen {
+		t.Errorf("Seen(b) after ttl = true; want false")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	store := &webhook.MemoryStore{Now: func() time.Time { return now }}
+	testSeenStore(t, store, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestFileStore(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), "seen.json")
+	store, err := webhook.NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() err = %v; want nil", err)
+	}
+	store.Now = func() time.Time { return now }
+	testSeenStore(t, store, func(d time.Duration) { now = now.Add(d) })
+
+	t.Run("persists", func(t *testing.T) {
+		store.Claim("c", time.Hour)
+		reopened, err := webhook.NewFileStore(path)
+		if err != nil {
+			t.Fatalf("NewFileStore() err = %v; want nil", err)
+		}
+		reopened.Now = store.Now
+		seen, err := reopened.Seen("c")
+		if err != nil || !seen {
+			t.Errorf("Seen(c) = %v, %v; want true, nil", seen, err)
+		}
+		seen, _ = reopened.Seen("a")
+		if seen {
+			t.Errorf("Seen(a) after Release = true; want false")
+		}
+	})
+
+	t.Run("partial last line", func(t *testing.T) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatalf("OpenFile() err = %v; want nil", err)
+		}
+		f.WriteString(`{"key":"g","exp`)
+		f.Close()
+		reopened, err := webhook.NewFileStore(path)
+		if err != nil {
+			t.Fatalf("NewFileStore() err = %v; want nil", err)
+		}
+		reopened.Now = store.Now
+		seen, _ := reopened.Seen("c")
+		if !seen {
+			t.Errorf("Seen(c) = false; want true")
+		}
+	})
+
+	t.Run("unwritable", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "seen.json")
+		store, err := webhook.NewFileStore(path)
+		if err != nil {
+			t.Fatalf("NewFileStore() err = %v; want nil", err)
+		}
+		_, err = store.Claim("h", time.Hour)
+		if err == nil {
+			t.Fatalf("Claim(h) err = nil; want error")
+		}
+		seen, _ := store.Seen("h")
+		if seen {
+			t.Errorf("Seen(h) after a failed Claim = true; want false")
+		}
+	})
+
+	t.Run("compacts", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "seen.json")
+		store, err := webhook.NewFileStore(path)
+		if err != nil {
+			t.Fatalf("NewFileStore() err = %v; want nil", err)
+		}
+		store.Now = func() time.Time { return now }
+		for i := 0; i < 250; i++ {
+			store.Claim(fmt.Sprint(i), time.Minute)
+			now = now.Add(time.Second)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile() err = %v; want nil", err)
+		}
+		// Keys expire after 60 claims, so the file is rewritten with at most
+		// 60 keys every 100 claims and then appended to.
+		if lines := bytes.Count(b, []byte("\n")); lines > 110 {
+			t.Errorf("lines = %d; want at most 110", lines)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// Query params used to encode a convertkit.WebhookEvent in a target URL.
+const (
+	ParamEvent          = "event"
+	ParamFormID         = "form_id"
+	ParamSequenceID     = "sequence_id"
+	ParamTagID          = "tag_id"
+	ParamProductID      = "product_id"
+	ParamInitiatorValue = "initiator_value"
+)
+
+// TargetURL returns the URL that should be registered with Convert Kit for
+// the given event. Convert Kit doesn't include the event in the webhook
+// payload, so it is encoded into the URL's query params where the Handler can
+// find it. Any query params already present in base are kept.
+func TargetURL(base string, event convertkit.WebhookEvent) (string, error) {
+	err := event.Validate()
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("webhook: parsing target url: %w", err)
+	}
+	query := u.Query()
+	query.Set(ParamEvent, string(event.Name))
+	setID := func(key string, id int) {
+		if id != 0 {
+			query.Set(key, strconv.Itoa(id))
+		}
+	}
+	setID(ParamFormID, int(event.FormID))
+	setID(ParamSequenceID, int(event.SequenceID))
+	setID(ParamTagID, int(event.TagID))
+	setID(ParamProductID, event.ProductID)
+	if event.InitiatorValue != "" {
+		query.Set(ParamInitiatorValue, event.InitiatorValue)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// EventFromURL parses the event encoded in a URL by TargetURL.
+func EventFromURL(u *url.URL) (convertkit.WebhookEvent, error) {
+	query := u.Query()
+	event := convertkit.WebhookEvent{
+		Name:           convertkit.WebhookEventName(query.Get(ParamEvent)),
+		InitiatorValue: query.Get(ParamInitiatorValue),
+	}
+	var ids [4]int
+	for i, key := range []string{ParamFormID, ParamSequenceID, ParamTagID, ParamProductID} {
+		v := query.Get(key)
+		if v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return event, fmt.Errorf("webhook: invalid %s %q", key, v)
+		}
+		ids[i] = id
+	}
+	event.FormID = convertkit.FormID(ids[0])
+	event.SequenceID = convertkit.SequenceID(ids[1])
+	event.TagID = convertkit.TagID(ids[2])
+	event.ProductID = ids[3]
+	err := event.Validate()
+	if err != nil {
+		return event, fmt.Errorf("webhook: %w", err)
+	}
+	return event, nil
+}
@@ -0,0 +1,61 @@
+package webhook_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func TestTargetURL(t *testing.T) {
+	for name, tc := range map[string]struct {
+		base  string
+		event convertkit.WebhookEvent
+		want  string
+	}{
+		"activate": {
+			base:  "https://example.com/webhooks",
+			event: convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberActivate},
+			want:  "https://example.com/webhooks?event=subscriber.subscriber_activate",
+		},
+		"existing query": {
+			base:  "https://example.com/webhooks?env=prod",
+			event: convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14},
+			want:  "https://example.com/webhooks?env=prod&event=subscriber.tag_add&tag_id=14",
+		},
+		"link click": {
+			base:  "https://example.com/webhooks",
+			event: convertkit.WebhookEvent{Name: convertkit.WebhookLinkClick, InitiatorValue: "https://example.com/?a=b"},
+			want:  "https://example.com/webhooks?event=subscriber.link_click&initiator_value=https%3A%2F%2Fexample.com%2F%3Fa%3Db",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := webhook.TargetURL(tc.base, tc.event)
+			if err != nil {
+				t.Fatalf("TargetURL() err = %v; want nil", err)
+			}
+			if got != tc.want {
+				t.Errorf("TargetURL() = %v; want %v", got, tc.want)
+			}
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("Parse() err = %v; want nil", err)
+			}
+			event, err := webhook.EventFromURL(u)
+			if err != nil {
+				t.Fatalf("EventFromURL() err = %v; want nil", err)
+			}
+			if event != tc.event {
+				t.Errorf("EventFromURL() = %+v; want %+v", event, tc.event)
+			}
+		})
+	}
+
+	t.Run("invalid event", func(t *testing.T) {
+		_, err := webhook.TargetURL("https://example.com", convertkit.WebhookEvent{Name: convertkit.WebhookFormSubscribe})
+		if err == nil {
+			t.Errorf("TargetURL() err = nil; want error for missing FormID")
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// ParamToken is the query param used to store the verification token in a
+// target URL.
+const ParamToken = "token"
+
+// Errors returned when a delivery can't be verified.
+var (
+	ErrInvalidToken   = errors.New("webhook: missing or invalid token")
+	ErrIPNotAllowed   = errors.New("webhook: source ip not allowed")
+	ErrNoSecrets      = errors.New("webhook: verifier has no secrets")
+	errInvalidAddress = errors.New("webhook: invalid source address")
+)
+
+// NewSecret returns a random secret suitable for use with a Verifier.
+func NewSecret() ([]byte, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: generating secret: %w", err)
+	}
+	return b, nil
+}
+
+// Verifier checks that webhook deliveries really came from Convert Kit.
+//
+// Convert Kit doesn't sign webhooks, so instead an HMAC token is added to the
+// target URL when the webhook is created. The token covers the URL's path and
+// every other query param, including the event, so a leaked URL for one event
+// or endpoint can't be used to forge deliveries for another. Since only
+// Convert Kit and your server know the full URL, deliveries with a valid token
+// can be trusted.
+//
+// The host isn't covered, since proxies often rewrite it, so endpoints on
+// different hosts that share a path should use different secrets. The path
+// must reach your server unchanged; if a proxy strips a prefix, sign the URL
+// with the path your server sees and add the prefix afterwards.
+//
+// To rotate secrets, add the new secret to the front of Secrets, re-create
+// your webhooks, and then remove the old secret once the old webhooks have
+// been deleted.
+type Verifier struct {
+	// Secrets are the secrets that tokens are accepted for. The first secret
+	// is used to sign new target URLs.
+	Secrets [][]byte
+	// AllowedNetworks, if not empty, limits deliveries to source IPs within
+	// one of these networks.
+	AllowedNetworks []*net.IPNet
+	// ClientIP returns the source IP of a request. It defaults to the host in
+	// r.RemoteAddr. Set this if your server is behind a proxy, but only trust
+	// headers that your proxy sets.
+	ClientIP func(r *http.Request) string
+}
+
+// ParseNetworks parses CIDR strings (eg "10.0.0.0/8") for use in
+// Verifier.AllowedNetworks. Single IPs are treated as a /32 or /128.
+func ParseNetworks(cidrs ...string) ([]*net.IPNet, error) {
+	var ret []*net.IPNet
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("webhook: invalid ip %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			ret = append(ret, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: %w", err)
+		}
+		ret = append(ret, n)
+	}
+	return ret, nil
+}
+
+// Sign adds a token to targetURL signed with the first secret. Any existing
+// token is replaced.
+func (v *Verifier) Sign(targetURL string) (string, error) {
+	if len(v.Secrets) == 0 {
+		return "", ErrNoSecrets
+	}
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return "", fmt.Errorf("webhook: parsing target url: %w", err)
+	}
+	query := u.Query()
+	query.Del(ParamToken)
+	query.Set(ParamToken, token(v.Secrets[0], u.EscapedPath(), query))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// TargetURL is like the package level TargetURL, but the URL is also signed.
+func (v *Verifier) TargetURL(base string, event convertkit.WebhookEvent) (string, error) {
+	target, err := TargetURL(base, event)
+	if err != nil {
+		return "", err
+	}
+	return v.Sign(target)
+}
+
+// CreateWebhook registers a webhook for the event with a signed target URL
+// built from base.
+func (v *Verifier) CreateWebhook(c *convertkit.Client, base string, event convertkit.WebhookEvent) (*convertkit.CreateWebhookResponse, error) {
+	target, err := v.TargetURL(base, event)
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateWebhook(convertkit.CreateWebhookRequest{
+		TargetURL: target,
+		Event:     event,
+	})
+}
+
+// Verify checks the token and source IP of r. Errors returned are
+// webhook.Error values with an appropriate status code.
+func (v *Verifier) Verify(r *http.Request) error {
+	if len(v.AllowedNetworks) > 0 {
+		err := v.verifyIP(r)
+		if err != nil {
+			return Error{StatusCode: http.StatusForbidden, Err: err}
+		}
+	}
+	if len(v.Secrets) == 0 {
+		return Error{StatusCode: http.StatusInternalServerError, Err: ErrNoSecrets}
+	}
+	query := r.URL.Query()
+	got, err := hex.DecodeString(query.Get(ParamToken))
+	if err != nil || len(got) == 0 {
+		return Error{StatusCode: http.StatusUnauthorized, Err: ErrInvalidToken}
+	}
+	query.Del(ParamToken)
+	valid := false
+	for _, secret := range v.Secrets {
+		want, _ := hex.DecodeString(token(secret, r.URL.EscapedPath(), query))
+		// Check every secret so timing doesn't reveal which one matched.
+		if hmac.Equal(got, want) {
+			valid = true
+		}
+	}
+	if !valid {
+		return Error{StatusCode: http.StatusUnauthorized, Err: ErrInvalidToken}
+	}
+	return nil
+}
+
+func (v *Verifier) verifyIP(r *http.Request) error {
+	var addr string
+	if v.ClientIP != nil {
+		addr = v.ClientIP(r)
+	} else {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		addr = host
+	}
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return errInvalidAddress
+	}
+	for _, n := range v.AllowedNetworks {
+		if n.Contains(ip) {
+			return nil
+		}
+	}
+	return ErrIPNotAllowed
+}
+
+// Middleware returns an http.Handler that only calls next for verified
+// deliveries. Unverified deliveries get a 401 or 403 response.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.Verify(r)
+		if err != nil {
+			status := http.StatusUnauthorized
+			var whErr Error
+			if errors.As(err, &whErr) {
+				status = whErr.StatusCode
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// token computes the HMAC of the path and the query params in a canonical
+// order.
+func token(secret []byte, path string, query url.Values) string {
+	if path == "" {
+		path = "/"
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d:%s", len(path), path)
+	for _, k := range keys {
+		for _, v := range query[k] {
+			fmt.Fprintf(mac, "%d:%s%d:%s", len(k), k, len(v), v)
+		}
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
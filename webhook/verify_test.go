@@ -0,0 +1,146 @@
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func TestVerifier(t *testing.T) {
+	oldSecret := []byte("old-secret")
+	newSecret := []byte("new-secret")
+	tagAdd := convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14}
+
+	signed := func(t *testing.T, v *webhook.Verifier) string {
+		target, err := v.TargetURL("https://example.com/webhooks?env=prod", tagAdd)
+		if err != nil {
+			t.Fatalf("TargetURL() err = %v; want nil", err)
+		}
+		return target
+	}
+	serve := func(v *webhook.Verifier, target, remoteAddr string) int {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(fixture(t, "subscriber")))
+		if remoteAddr != "" {
+			r.RemoteAddr = remoteAddr
+		}
+		w := httptest.NewRecorder()
+		v.Middleware(next).ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		v := &webhook.Verifier{Secrets: [][]byte{oldSecret}}
+		if got := serve(v, signed(t, v), ""); got != http.StatusNoContent {
+			t.Errorf("StatusCode = %d; want %d", got, http.StatusNoContent)
+		}
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		v := &webhook.Verifier{Secrets: [][]byte{oldSecret}}
+		target, _ := webhook.TargetURL("https://example.com/webhooks", tagAdd)
+		if got := serve(v, target, ""); got != http.StatusUnauthorized {
+			t.Errorf("StatusCode = %d; want %d", got, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("tampered event", func(t *testing.T) {
+		v := &webhook.Verifier{Secrets: [][]byte{oldSecret}}
+		target := strings.Replace(signed(t, v), "tag_id=14", "tag_id=15", 1)
+		if got := serve(v, target, ""); got != http.StatusUnauthorized {
+			t.Errorf("StatusCode = %d; want %d", got, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("other path", func(t *testing.T) {
+		v := &webhook.Verifier{Secrets: [][]byte{oldSecret}}
+		target := strings.Replace(signed(t, v), "/webhooks", "/admin/webhooks", 1)
+		if got := serve(v, target, ""); got != http.StatusUnauthorized {
+			t.Errorf("StatusCode = %d; want %d", got, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		before := &webhook.Verifier{Secrets: [][]byte{oldSecret}}
+		during := &webhook.Verifier{Secrets: [][]byte{newSecret, oldSecret}}
+		after := &webhook.Verifier{Secrets: [][]byte{newSecret}}
+		oldTarget, newTarget := signed(t, before), signed(t, during)
+		if got := serve(during, oldTarget, ""); got != http.StatusNoContent {
+			t.Errorf("during(old) StatusCode = %d; want %d", got, http.StatusNoContent)
+		}
+		if got := serve(during, newTarget, ""); got != http.StatusNoContent {
+			t.Errorf("during(new) StatusCode = %d; want %d", got, http.StatusNoContent)
+		}
+		if got := serve(after, oldTarget, ""); got != http.StatusUnauthorized {
+			t.Errorf("after(old) StatusCode = %d; want %d", got, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("ip allowlist", func(t *testing.T) {
+		networks, err := webhook.ParseNetworks("10.0.0.0/8", "192.168.1.7")
+		if err != nil {
+			t.Fatalf("ParseNetworks() err = %v; want nil", err)
+		}
+		v := &webhook.Verifier{
+			Secrets:         [][]byte{oldSecret},
+			AllowedNetworks: networks,
+		}
+		target := signed(t, v)
+		for addr, want := range map[string]int{
+			"10.1.2.3:1234":    http.StatusNoContent,
+			"192.168.1.7:1234": http.StatusNoContent,
+			"192.168.1.8:1234": http.StatusForbidden,
+			"[::1]:1234":       http.StatusForbidden,
+		} {
+			if got := serve(v, target, addr); got != want {
+				t.Errorf("%v: StatusCode = %d; want %d", addr, got, want)
+			}
+		}
+	})
+}
+
+func TestVerifier_CreateWebhook(t *testing.T) {
+	var gotTarget string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			TargetURL string `json:"target_url"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		gotTarget = body.TargetURL
+		fmt.Fprintf(w, `{"rule": {"id": 1, "event": {"name": "subscriber.tag_add", "tag_id": 14}, "target_url": %q}}`, body.TargetURL)
+	}))
+	defer server.Close()
+	c := &convertkit.Client{Secret: "fake-secret-key", BaseURL: server.URL}
+	v := &webhook.Verifier{Secrets: [][]byte{[]byte("secret")}}
+	_, err := v.CreateWebhook(c, "https://example.com/webhooks", convertkit.WebhookEvent{
+		Name:  convertkit.WebhookTagAdd,
+		TagID: 14,
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook() err = %v; want nil", err)
+	}
+	u, err := url.Parse(gotTarget)
+	if err != nil {
+		t.Fatalf("Parse() err = %v; want nil", err)
+	}
+	if u.Query().Get(webhook.ParamToken) == "" {
+		t.Errorf("target_url = %v; want a token", gotTarget)
+	}
+	r := httptest.NewRequest(http.MethodPost, gotTarget, nil)
+	if err := v.Verify(r); err != nil {
+		t.Errorf("Verify() err = %v; want nil", err)
+	}
+}
@@ -0,0 +1,235 @@
+// Package webhook receives webhooks sent by Convert Kit and dispatches them to
+// typed event handlers.
+//
+// Convert Kit doesn't include the event that triggered a webhook in its
+// payload, so this package encodes the event in the target URL registered with
+// Convert Kit. Use TargetURL to build these URLs, then register handlers for
+// each event type on a Handler:
+//
+//	var h webhook.Handler
+//	h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+//		return grantCourseAccess(ctx, e.Subscriber.Email)
+//	})
+//	http.Handle("/webhooks/convertkit", &h)
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+// MaxBodySize is the largest webhook body that will be read.
+const MaxBodySize = 1 << 20
+
+// Error can be returned from an event handler to control the HTTP status code
+// sent back to Convert Kit. Any other error results in a 500. Convert Kit
+// retries deliveries that fail, so return a 4xx status for events that should
+// not be retried.
+type Error struct {
+	StatusCode int
+	Err        error
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%d - %v", e.StatusCode, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Delivery is a single webhook request sent by Convert Kit.
+type Delivery struct {
+	Event convertkit.WebhookEvent
+	Body  []byte
+}
+
+// Decode decodes the delivery's body into a typed Event.
+func (d Delivery) Decode() (Event, error) {
+	return Decode(d.Event, d.Body)
+}
+
+// ReadDelivery reads the webhook event and body from r. The body of r is
+// replaced so it can be read again by other handlers.
+func ReadDelivery(r *http.Request) (*Delivery, error) {
+	if r.Method != http.MethodPost {
+		return nil, Error{
+			StatusCode: http.StatusMethodNotAllowed,
+			Err:        fmt.Errorf("webhook: method %s not allowed", r.Method),
+		}
+	}
+	event, err := EventFromURL(r.URL)
+	if err != nil {
+		return nil, Error{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, MaxBodySize))
+	if err != nil {
+		return nil, Error{StatusCode: http.StatusBadRequest, Err: fmt.Errorf("webhook: reading body: %w", err)}
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return &Delivery{
+		Event: event,
+		Body:  body,
+	}, nil
+}
+
+// Handler is an http.Handler that decodes Convert Kit webhooks and passes
+// them to the handler registered for their event. The zero value is ready to
+// use.
+//
+// Deliveries for events without a registered handler are acknowledged with a
+// 204 so that Convert Kit doesn't retry them.
+type Handler struct {
+	// OnError, if set, is called with any error that results in a non-2xx
+	// response.
+	OnError func(r *http.Request, err error)
+
+	mu       sync.RWMutex
+	handlers map[convertkit.WebhookEventName]func(context.Context, Event) error
+}
+
+func (h *Handler) on(name convertkit.WebhookEventName, fn func(context.Context, Event) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = make(map[convertkit.WebhookEventName]func(context.Context, Event) error)
+	}
+	h.handlers[name] = fn
+}
+
+// Handle registers fn for every event with the given name. This is mostly
+// useful for generic handlers, eg logging every event. The typed On* methods
+// should be preferred otherwise.
+func (h *Handler) Handle(name convertkit.WebhookEventName, fn func(context.Context, Event) error) {
+	h.on(name, fn)
+}
+
+// OnSubscriberActivate registers the handler for subscriber activate events.
+func (h *Handler) OnSubscriberActivate(fn func(context.Context, SubscriberActivateEvent) error) {
+	h.on(convertkit.WebhookSubscriberActivate, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(SubscriberActivateEvent))
+	})
+}
+
+// OnSubscriberUnsubscribe registers the handler for subscriber unsubscribe
+// events.
+func (h *Handler) OnSubscriberUnsubscribe(fn func(context.Context, SubscriberUnsubscribeEvent) error) {
+	h.on(convertkit.WebhookSubscriberUnsubscribe, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(SubscriberUnsubscribeEvent))
+	})
+}
+
+// OnSubscriberBounce registers the handler for subscriber bounce events.
+func (h *Handler) OnSubscriberBounce(fn func(context.Context, SubscriberBounceEvent) error) {
+	h.on(convertkit.WebhookSubscriberBounce, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(SubscriberBounceEvent))
+	})
+}
+
+// OnSubscriberComplain registers the handler for subscriber complain events.
+func (h *Handler) OnSubscriberComplain(fn func(context.Context, SubscriberComplainEvent) error) {
+	h.on(convertkit.WebhookSubscriberComplain, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(SubscriberComplainEvent))
+	})
+}
+
+// OnFormSubscribe registers the handler for form subscribe events.
+func (h *Handler) OnFormSubscribe(fn func(context.Context, FormSubscribeEvent) error) {
+	h.on(convertkit.WebhookFormSubscribe, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(FormSubscribeEvent))
+	})
+}
+
+// OnSequenceSubscribe registers the handler for sequence subscribe events.
+func (h *Handler) OnSequenceSubscribe(fn func(context.Context, SequenceSubscribeEvent) error) {
+	h.on(convertkit.WebhookSequenceSubscribe, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(SequenceSubscribeEvent))
+	})
+}
+
+// OnSequenceComplete registers the handler for sequence complete events.
+func (h *Handler) OnSequenceComplete(fn func(context.Context, SequenceCompleteEvent) error) {
+	h.on(convertkit.WebhookSequenceComplete, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(SequenceCompleteEvent))
+	})
+}
+
+// OnTagAdd registers the handler for tag add events.
+func (h *Handler) OnTagAdd(fn func(context.Context, TagAddEvent) error) {
+	h.on(convertkit.WebhookTagAdd, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(TagAddEvent))
+	})
+}
+
+// OnTagRemove registers the handler for tag remove events.
+func (h *Handler) OnTagRemove(fn func(context.Context, TagRemoveEvent) error) {
+	h.on(convertkit.WebhookTagRemove, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(TagRemoveEvent))
+	})
+}
+
+// OnLinkClick registers the handler for link click events.
+func (h *Handler) OnLinkClick(fn func(context.Context, LinkClickEvent) error) {
+	h.on(convertkit.WebhookLinkClick, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(LinkClickEvent))
+	})
+}
+
+// OnProductPurchase registers the handler for product purchase events.
+func (h *Handler) OnProductPurchase(fn func(context.Context, ProductPurchaseEvent) error) {
+	h.on(convertkit.WebhookProductPurchase, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(ProductPurchaseEvent))
+	})
+}
+
+// OnPurchaseCreate registers the handler for purchase create events.
+func (h *Handler) OnPurchaseCreate(fn func(context.Context, PurchaseCreateEvent) error) {
+	h.on(convertkit.WebhookPurchaseCreate, func(ctx context.Context, e Event) error {
+		return fn(ctx, e.(PurchaseCreateEvent))
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.serve(r)
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	status := http.StatusInternalServerError
+	var whErr Error
+	if errors.As(err, &whErr) {
+		status = whErr.StatusCode
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+func (h *Handler) serve(r *http.Request) error {
+	d, err := ReadDelivery(r)
+	if err != nil {
+		return err
+	}
+	h.mu.RLock()
+	fn, ok := h.handlers[d.Event.Name]
+	h.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	event, err := d.Decode()
+	if err != nil {
+		return Error{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	return fn(r.Context(), event)
+}
@@ -0,0 +1,236 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+	"github.com/joncalhoun/convertkit/webhook"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s. err = %v", name, err)
+	}
+	return b
+}
+
+func deliveryRequest(t *testing.T, event convertkit.WebhookEvent, body []byte) *http.Request {
+	target, err := webhook.TargetURL("https://example.com/webhooks", event)
+	if err != nil {
+		t.Fatalf("TargetURL() err = %v; want nil", err)
+	}
+	return httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+}
+
+func TestHandler_dispatch(t *testing.T) {
+	var h webhook.Handler
+	var got webhook.Event
+	record := func(ctx context.Context, e webhook.Event) error {
+		got = e
+		return nil
+	}
+	h.OnSubscriberActivate(func(ctx context.Context, e webhook.SubscriberActivateEvent) error { return record(ctx, e) })
+	h.OnSubscriberUnsubscribe(func(ctx context.Context, e webhook.SubscriberUnsubscribeEvent) error { return record(ctx, e) })
+	h.OnSubscriberBounce(func(ctx context.Context, e webhook.SubscriberBounceEvent) error { return record(ctx, e) })
+	h.OnSubscriberComplain(func(ctx context.Context, e webhook.SubscriberComplainEvent) error { return record(ctx, e) })
+	h.OnFormSubscribe(func(ctx context.Context, e webhook.FormSubscribeEvent) error { return record(ctx, e) })
+	h.OnSequenceSubscribe(func(ctx context.Context, e webhook.SequenceSubscribeEvent) error { return record(ctx, e) })
+	h.OnSequenceComplete(func(ctx context.Context, e webhook.SequenceCompleteEvent) error { return record(ctx, e) })
+	h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error { return record(ctx, e) })
+	h.OnTagRemove(func(ctx context.Context, e webhook.TagRemoveEvent) error { return record(ctx, e) })
+	h.OnLinkClick(func(ctx context.Context, e webhook.LinkClickEvent) error { return record(ctx, e) })
+	h.OnProductPurchase(func(ctx context.Context, e webhook.ProductPurchaseEvent) error { return record(ctx, e) })
+	h.OnPurchaseCreate(func(ctx context.Context, e webhook.PurchaseCreateEvent) error { return record(ctx, e) })
+
+	var p webhook.Payload
+	err := json.Unmarshal(fixture(t, "subscriber"), &p)
+	if err != nil {
+		t.Fatalf("Unmarshal() err = %v; want nil", err)
+	}
+	sub := *p.Subscriber
+
+	for name, tc := range map[string]struct {
+		event   convertkit.WebhookEvent
+		fixture string
+		want    webhook.Event
+	}{
+		"activate": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberActivate},
+			fixture: "subscriber",
+			want:    webhook.SubscriberActivateEvent{Subscriber: sub},
+		},
+		"unsubscribe": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberUnsubscribe},
+			fixture: "subscriber",
+			want:    webhook.SubscriberUnsubscribeEvent{Subscriber: sub},
+		},
+		"bounce": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberBounce},
+			fixture: "subscriber",
+			want:    webhook.SubscriberBounceEvent{Subscriber: sub},
+		},
+		"complain": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberComplain},
+			fixture: "subscriber",
+			want:    webhook.SubscriberComplainEvent{Subscriber: sub},
+		},
+		"form subscribe": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookFormSubscribe, FormID: 213},
+			fixture: "subscriber",
+			want:    webhook.FormSubscribeEvent{Subscriber: sub, Form: convertkit.Form{ID: 213}},
+		},
+		"sequence subscribe": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookSequenceSubscribe, SequenceID: 55},
+			fixture: "subscriber",
+			want:    webhook.SequenceSubscribeEvent{Subscriber: sub, Sequence: convertkit.Sequence{ID: 55}},
+		},
+		"sequence complete": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookSequenceComplete, SequenceID: 55},
+			fixture: "subscriber",
+			want:    webhook.SequenceCompleteEvent{Subscriber: sub, Sequence: convertkit.Sequence{ID: 55}},
+		},
+		"tag add": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14},
+			fixture: "subscriber",
+			want:    webhook.TagAddEvent{Subscriber: sub, Tag: convertkit.Tag{ID: 14}},
+		},
+		"tag remove": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookTagRemove, TagID: 14},
+			fixture: "subscriber",
+			want:    webhook.TagRemoveEvent{Subscriber: sub, Tag: convertkit.Tag{ID: 14}},
+		},
+		"link click": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookLinkClick, InitiatorValue: "https://example.com/?a=b"},
+			fixture: "subscriber",
+			want:    webhook.LinkClickEvent{Subscriber: sub, URL: "https://example.com/?a=b"},
+		},
+		"product purchase": {
+			event:   convertkit.WebhookEvent{Name: convertkit.WebhookProductPurchase, ProductID: 9999},
+			fixture: "subscriber",
+			want:    webhook.ProductPurchaseEvent{Subscriber: sub, ProductID: 9999},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got = nil
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, deliveryRequest(t, tc.event, fixture(t, tc.fixture)))
+			if w.Code != http.StatusNoContent {
+				t.Errorf("StatusCode = %d; want %d", w.Code, http.StatusNoContent)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("event = %+v; want %+v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("purchase create", func(t *testing.T) {
+		got = nil
+		w := httptest.NewRecorder()
+		event := convertkit.WebhookEvent{Name: convertkit.WebhookPurchaseCreate}
+		h.ServeHTTP(w, deliveryRequest(t, event, fixture(t, "purchase")))
+		if w.Code != http.StatusNoContent {
+			t.Errorf("StatusCode = %d; want %d", w.Code, http.StatusNoContent)
+		}
+		pc, ok := got.(webhook.PurchaseCreateEvent)
+		if !ok {
+			t.Fatalf("event type = %T; want %T", got, pc)
+		}
+		if pc.Purchase.TransactionID != "123-abcd-456-efgh" {
+			t.Errorf("TransactionID = %v; want %v", pc.Purchase.TransactionID, "123-abcd-456-efgh")
+		}
+		if len(pc.Purchase.Products) != 2 {
+			t.Errorf("len(Products) = %d; want 2", len(pc.Purchase.Products))
+		}
+	})
+}
+
+func TestHandler_statusCodes(t *testing.T) {
+	tagAdd := convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14}
+	for name, tc := range map[string]struct {
+		handlerErr error
+		req        func(t *testing.T) *http.Request
+		want       int
+	}{
+		"handler error": {
+			handlerErr: errors.New("database is down"),
+			req: func(t *testing.T) *http.Request {
+				return deliveryRequest(t, tagAdd, fixture(t, "subscriber"))
+			},
+			want: http.StatusInternalServerError,
+		},
+		"handler status": {
+			handlerErr: webhook.Error{StatusCode: http.StatusUnprocessableEntity, Err: errors.New("unknown course")},
+			req: func(t *testing.T) *http.Request {
+				return deliveryRequest(t, tagAdd, fixture(t, "subscriber"))
+			},
+			want: http.StatusUnprocessableEntity,
+		},
+		"unregistered event": {
+			req: func(t *testing.T) *http.Request {
+				return deliveryRequest(t, convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberBounce}, fixture(t, "subscriber"))
+			},
+			want: http.StatusNoContent,
+		},
+		"wrong method": {
+			req: func(t *testing.T) *http.Request {
+				r := deliveryRequest(t, tagAdd, nil)
+				r.Method = http.MethodGet
+				return r
+			},
+			want: http.StatusMethodNotAllowed,
+		},
+		"missing event": {
+			req: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(fixture(t, "subscriber")))
+			},
+			want: http.StatusBadRequest,
+		},
+		"missing tag id": {
+			req: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/webhooks?event=subscriber.tag_add", bytes.NewReader(fixture(t, "subscriber")))
+			},
+			want: http.StatusBadRequest,
+		},
+		"malformed body": {
+			req: func(t *testing.T) *http.Request {
+				return deliveryRequest(t, tagAdd, []byte(`{"subscriber":`))
+			},
+			want: http.StatusBadRequest,
+		},
+		"missing subscriber": {
+			req: func(t *testing.T) *http.Request {
+				return deliveryRequest(t, tagAdd, []byte(`{}`))
+			},
+			want: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var h webhook.Handler
+			var gotErr error
+			h.OnError = func(r *http.Request, err error) {
+				gotErr = err
+			}
+			h.OnTagAdd(func(ctx context.Context, e webhook.TagAddEvent) error {
+				return tc.handlerErr
+			})
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tc.req(t))
+			if w.Code != tc.want {
+				t.Errorf("StatusCode = %d; want %d", w.Code, tc.want)
+			}
+			if tc.want >= 400 && gotErr == nil {
+				t.Errorf("OnError() not called")
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package convertkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WebhookEventName is the name of an event that a webhook can be registered
+// for.
+type WebhookEventName string
+
+// WebhookEventNames supported by the API.
+const (
+	WebhookSubscriberActivate    WebhookEventName = "subscriber.subscriber_activate"
+	WebhookSubscriberUnsubscribe WebhookEventName = "subscriber.subscriber_unsubscribe"
+	WebhookSubscriberBounce      WebhookEventName = "subscriber.subscriber_bounce"
+	WebhookSubscriberComplain    WebhookEventName = "subscriber.subscriber_complain"
+	WebhookFormSubscribe         WebhookEventName = "subscriber.form_subscribe"
+	WebhookSequenceSubscribe     WebhookEventName = "subscriber.course_subscribe"
+	WebhookSequenceComplete      WebhookEventName = "subscriber.course_complete"
+	WebhookLinkClick             WebhookEventName = "subscriber.link_click"
+	WebhookProductPurchase       WebhookEventName = "subscriber.product_purchase"
+	WebhookTagAdd                WebhookEventName = "subscriber.tag_add"
+	WebhookTagRemove             WebhookEventName = "subscriber.tag_remove"
+	WebhookPurchaseCreate        WebhookEventName = "purchase.purchase_create"
+)
+
+// WebhookEvent describes the event a webhook is triggered by. Some events
+// require an additional ID, eg WebhookTagAdd requires a TagID.
+//
+// Sequences were previously called courses, which is why the sequence events
+// are named the way they are.
+type WebhookEvent struct {
+	// Required
+	Name WebhookEventName `json:"name"`
+	// Required for some events
+	FormID     FormID     `json:"form_id,omitempty"`
+	SequenceID SequenceID `json:"sequence_id,omitempty"`
+	TagID      TagID      `json:"tag_id,omitempty"`
+	ProductID  int        `json:"product_id,omitempty"`
+	// InitiatorValue is the URL of the link for WebhookLinkClick events.
+	InitiatorValue string `json:"initiator_value,omitempty"`
+}
+
+// Validate checks the event for missing or malformed fields.
+func (e WebhookEvent) Validate() error {
+	var v validator
+	switch e.Name {
+	case WebhookSubscriberActivate, WebhookSubscriberUnsubscribe,
+		WebhookSubscriberBounce, WebhookSubscriberComplain,
+		WebhookPurchaseCreate:
+	case WebhookFormSubscribe:
+		v.requireID("FormID", int(e.FormID))
+	case WebhookSequenceSubscribe, WebhookSequenceComplete:
+		v.requireID("SequenceID", int(e.SequenceID))
+	case WebhookTagAdd, WebhookTagRemove:
+		v.requireID("TagID", int(e.TagID))
+	case WebhookProductPurchase:
+		v.requireID("ProductID", e.ProductID)
+	case WebhookLinkClick:
+		if e.InitiatorValue == "" {
+			v.add("InitiatorValue", "is required")
+		}
+	case "":
+		v.add("Name", "is required")
+	default:
+		v.add("Name", "is not a supported webhook event")
+	}
+	return v.err()
+}
+
+// WebhookRule is a webhook registered with Convert Kit.
+type WebhookRule struct {
+	Raw       *RawJSON      `json:"-"`
+	ID        WebhookRuleID `json:"id"`
+	AccountID int           `json:"account_id"`
+	Event     WebhookEvent  `json:"event"`
+	TargetURL string        `json:"target_url"`
+}
+
+// CreateWebhookRequest is used when making CreateWebhook calls.
+type CreateWebhookRequest struct {
+	// Required
+	TargetURL string       `json:"target_url"`
+	Event     WebhookEvent `json:"event"`
+}
+
+// Validate checks the request for missing or malformed fields.
+func (r CreateWebhookRequest) Validate() error {
+	var v validator
+	u, err := url.Parse(r.TargetURL)
+	switch {
+	case r.TargetURL == "":
+		v.add("TargetURL", "is required")
+	case err != nil || !u.IsAbs() || u.Host == "":
+		v.add("TargetURL", fmt.Sprintf("%q is not an absolute URL", r.TargetURL))
+	}
+	var eventErr ValidationError
+	if errors.As(r.Event.Validate(), &eventErr) {
+		for _, fe := range eventErr.Fields {
+			v.add("Event."+fe.Field, fe.Message)
+		}
+	}
+	return v.err()
+}
+
+// CreateWebhookResponse is the response data from CreateWebhook.
+type CreateWebhookResponse struct {
+	RawJSON `json:"-"`
+	Rule    WebhookRule `json:"rule"`
+}
+
+// CreateWebhook registers a webhook that Convert Kit will call whenever the
+// event occurs.
+func (c *Client) CreateWebhook(req CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	var ret CreateWebhookResponse
+	err := c.Do(http.MethodPost, "automations/hooks", req, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+type deleteWebhookRequest struct {
+	RuleID WebhookRuleID `json:"-"`
+}
+
+func (r deleteWebhookRequest) Validate() error {
+	var v validator
+	v.requireID("RuleID", int(r.RuleID))
+	return v.err()
+}
+
+// DeleteWebhookResponse is the response data from DeleteWebhook.
+type DeleteWebhookResponse struct {
+	RawJSON `json:"-"`
+	Success bool `json:"success"`
+}
+
+// DeleteWebhook removes a webhook so it will no longer be called.
+func (c *Client) DeleteWebhook(ruleID WebhookRuleID) (*DeleteWebhookResponse, error) {
+	req := deleteWebhookRequest{RuleID: ruleID}
+	var ret DeleteWebhookResponse
+	err := c.Do(http.MethodDelete, fmt.Sprintf("automations/hooks/%v", ruleID), req, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
@@ -0,0 +1,111 @@
+package convertkit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/joncalhoun/convertkit"
+)
+
+func TestWebhookEvent_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		event   convertkit.WebhookEvent
+		wantErr bool
+	}{
+		"activate":          {event: convertkit.WebhookEvent{Name: convertkit.WebhookSubscriberActivate}},
+		"tag add":           {event: convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd, TagID: 14}},
+		"tag add no id":     {event: convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd}, wantErr: true},
+		"form no id":        {event: convertkit.WebhookEvent{Name: convertkit.WebhookFormSubscribe}, wantErr: true},
+		"link click no url": {event: convertkit.WebhookEvent{Name: convertkit.WebhookLinkClick}, wantErr: true},
+		"missing name":      {event: convertkit.WebhookEvent{}, wantErr: true},
+		"unknown name":      {event: convertkit.WebhookEvent{Name: "subscriber.dance"}, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.event.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() err = %v; want error = %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_CreateWebhook(t *testing.T) {
+	c := client(t, "fake-secret-key")
+	t.Run("basic check", func(t *testing.T) {
+		resp, err := c.CreateWebhook(convertkit.CreateWebhookRequest{
+			TargetURL: "https://example.com/webhooks?event=subscriber.tag_add&tag_id=14",
+			Event: convertkit.WebhookEvent{
+				Name:  convertkit.WebhookTagAdd,
+				TagID: 14,
+			},
+		})
+		if err != nil {
+			t.Fatalf("CreateWebhook() err = %v; want %v", err, nil)
+		}
+		if resp.Rule.ID != 1 {
+			t.Errorf("Rule.ID = %v; want 1", resp.Rule.ID)
+		}
+		if resp.Rule.Event.TagID != 14 {
+			t.Errorf("Rule.Event.TagID = %v; want 14", resp.Rule.Event.TagID)
+		}
+	})
+
+	t.Run("options are sent to server", func(t *testing.T) {
+		c := clientWithHandler(t, func(w http.ResponseWriter, r *http.Request) {
+			var gotBody map[string]interface{}
+			err := json.NewDecoder(r.Body).Decode(&gotBody)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			want := map[string]interface{}{
+				"name":   "subscriber.tag_add",
+				"tag_id": 14.0,
+			}
+			if !reflect.DeepEqual(gotBody["event"], want) {
+				t.Errorf("event = %v; want %v", gotBody["event"], want)
+			}
+			if gotBody["target_url"] != "https://example.com/webhooks" {
+				t.Errorf("target_url = %v; want %v", gotBody["target_url"], "https://example.com/webhooks")
+			}
+			testdataHandler(t, "POST_automations_hooks")(w, r)
+		})
+		_, err := c.CreateWebhook(convertkit.CreateWebhookRequest{
+			TargetURL: "https://example.com/webhooks",
+			Event: convertkit.WebhookEvent{
+				Name:  convertkit.WebhookTagAdd,
+				TagID: 14,
+			},
+		})
+		if err != nil {
+			t.Fatalf("CreateWebhook() err = %v; want nil", err)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := c.CreateWebhook(convertkit.CreateWebhookRequest{
+			TargetURL: "/webhooks",
+			Event:     convertkit.WebhookEvent{Name: convertkit.WebhookTagAdd},
+		})
+		var vErr convertkit.ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("CreateWebhook() err type = %T; want %T", err, vErr)
+		}
+		if len(vErr.Fields) != 2 {
+			t.Errorf("Fields = %v; want TargetURL and Event.TagID", vErr.Fields)
+		}
+	})
+}
+
+func TestClient_DeleteWebhook(t *testing.T) {
+	c := client(t, "fake-secret-key")
+	resp, err := c.DeleteWebhook(1)
+	if err != nil {
+		t.Fatalf("DeleteWebhook() err = %v; want %v", err, nil)
+	}
+	if !resp.Success {
+		t.Errorf("Success = false; want true")
+	}
+}